@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 
 	"github.com/awa/go-iap/appstore"
 	"github.com/linhoi/gopay/common/httpx"
@@ -74,10 +75,24 @@ func NewClient(config *Config) *Client {
 	}
 }
 
+// Config returns the config of the app.
+func (c *Client) Config() Config {
+	return *c.config
+}
+
+// APIKey returns the App Store Connect API key of the app.
+func (c *Client) APIKey() (APIKey, error) {
+	if c.config.KeyID == "" || c.config.IssuerID == "" || c.config.PrivateKey == "" {
+		return APIKey{}, fmt.Errorf("%w: %s", ErrNoAPIKey, c.config.BundleID)
+	}
+
+	return APIKey{KeyID: c.config.KeyID, IssuerID: c.config.IssuerID, PrivateKey: c.config.PrivateKey}, nil
+}
+
 // Verify : reference https://developer.apple.com/documentation/storekit/original_api_for_in-app_purchase/validating_receipts_with_the_app_store#//apple_ref/doc/uid/TP40010573-CH104-SW1
 func (c *Client) Verify(ctx context.Context, receipt string) (*appstore.IAPResponse, error) {
 	resp := &appstore.IAPResponse{}
-	err := c.appstore.Verify(ctx, appstore.IAPRequest{ReceiptData: receipt}, &resp)
+	err := c.appstore.Verify(ctx, appstore.IAPRequest{ReceiptData: receipt, Password: c.config.PSW}, &resp)
 	if err != nil {
 		log.L(ctx).Warn("appstore verify failed", zap.Error(err))
 		return nil, err
@@ -9,7 +9,8 @@ var (
 	// ErrInvalidSignature returns when parse a receipt
 	// which improperly signed.
 	ErrInvalidSignature = errors.New("invalid signature of receipt")
-
-
-
+	// ErrUnknownBundle returns when the bundle id is not configured in the Registry.
+	ErrUnknownBundle = errors.New("unknown bundle id")
+	// ErrNoAPIKey returns when the App Store Connect API key of the bundle is not configured.
+	ErrNoAPIKey = errors.New("app store connect api key is not configured")
 )
@@ -0,0 +1,187 @@
+package apple
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/awa/go-iap/appstore"
+	"github.com/linhoi/kit/log"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+// RegistryConfig configures the apps served by a Registry, apps may belong to different App Store Connect accounts.
+type RegistryConfig struct {
+	Apps []Config `yaml:"apps"`
+}
+
+// Registry selects the Client of an app by bundle id.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+	// reloadMu serializes Reload, so a reload never builds on the clients another one is replacing.
+	reloadMu sync.Mutex
+}
+
+var _ IAP = (*Registry)(nil)
+
+// NewRegistry ...
+func NewRegistry(config RegistryConfig) (*Registry, error) {
+	r := &Registry{}
+	if err := r.Reload(config); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// LoadRegistry creates a Registry from yaml.
+func LoadRegistry(data []byte) (*Registry, error) {
+	var config RegistryConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	return NewRegistry(config)
+}
+
+// Reload replaces the apps of the registry, clients of unchanged apps are kept.
+// The registry is left untouched if the config is invalid.
+func (r *Registry) Reload(config RegistryConfig) error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	r.mu.RLock()
+	current := r.clients
+	r.mu.RUnlock()
+
+	clients := make(map[string]*Client, len(config.Apps))
+	for i := range config.Apps {
+		app := config.Apps[i]
+		if app.BundleID == "" {
+			return fmt.Errorf("apple registry app bundle_id is empty")
+		}
+		if _, exist := clients[app.BundleID]; exist {
+			return fmt.Errorf("apple registry app %s is duplicated", app.BundleID)
+		}
+
+		if c, ok := current[app.BundleID]; ok && *c.config == app {
+			clients[app.BundleID] = c
+			continue
+		}
+		clients[app.BundleID] = NewClient(&app)
+	}
+
+	r.mu.Lock()
+	r.clients = clients
+	r.mu.Unlock()
+
+	return nil
+}
+
+// ReloadFile reloads the registry from a yaml file.
+func (r *Registry) ReloadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var config RegistryConfig
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return err
+	}
+
+	return r.Reload(config)
+}
+
+// WatchFile reloads the registry from path every interval until ctx is done.
+func (r *Registry) WatchFile(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.ReloadFile(path); err != nil {
+				log.L(ctx).Warn("apple registry reload failed", zap.Error(err), zap.String("path", path))
+			}
+		}
+	}
+}
+
+// Client returns the client of the bundle.
+func (r *Registry) Client(bundleID string) (*Client, error) {
+	r.mu.RLock()
+	c, ok := r.clients[bundleID]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBundle, bundleID)
+	}
+
+	return c, nil
+}
+
+// APIKey returns the App Store Connect API key of the bundle.
+func (r *Registry) APIKey(bundleID string) (APIKey, error) {
+	c, err := r.Client(bundleID)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	return c.APIKey()
+}
+
+// clientByReceipt selects the client by the bundle id in the receipt.
+func (r *Registry) clientByReceipt(ctx context.Context, receipt string) (*Client, error) {
+	bundleID, err := ParseBundleID(receipt)
+	if err != nil {
+		log.L(ctx).Warn("parse receipt bundle id failed", zap.Error(err))
+		return nil, err
+	}
+
+	return r.Client(bundleID)
+}
+
+// LocalValidateReceipt validates the receipt with the root ca of its bundle.
+func (r *Registry) LocalValidateReceipt(ctx context.Context, receipt string) (*Receipts, error) {
+	c, err := r.clientByReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.LocalValidateReceipt(ctx, receipt)
+}
+
+// Verify verifies the receipt with the shared secret of its bundle.
+func (r *Registry) Verify(ctx context.Context, receipt string) (*appstore.IAPResponse, error) {
+	c, err := r.clientByReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Verify(ctx, receipt)
+}
+
+// OnNotificationV1 deals the notification with the client of its bid.
+func (r *Registry) OnNotificationV1(ctx context.Context, notificationBody []byte, iapHandel IAPHandle, notifyHandle NotifyHandle) error {
+	var notify NotificationV1
+	err := json.Unmarshal(notificationBody, &notify)
+	if err != nil {
+		log.L(ctx).Warn("on notify josn unmarshal failed", zap.Error(err))
+		return nil
+	}
+
+	c, err := r.Client(notify.Bid)
+	if err != nil {
+		log.L(ctx).Warn("on notify bundle is not registered", zap.Error(err))
+		return err
+	}
+
+	return c.OnNotificationV1(ctx, notificationBody, iapHandel, notifyHandle)
+}
@@ -0,0 +1,130 @@
+package apple
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awa/go-iap/appstore"
+)
+
+func TestRegistry_OnNotificationV1(t *testing.T) {
+	var gotPassword string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req appstore.IAPRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotPassword = req.Password
+		_, _ = w.Write([]byte(`{"status": 0}`))
+	}))
+	defer server.Close()
+
+	r, err := LoadRegistry([]byte(`
+apps:
+  - bundle_id: com.example.a
+    psw: secret-a
+  - bundle_id: com.example.b
+    psw: secret-b
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bundleID := range []string{"com.example.a", "com.example.b"} {
+		c, err := r.Client(bundleID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.appstore.ProductionURL = server.URL
+	}
+
+	tests := []struct {
+		name         string
+		bid          string
+		wantPassword string
+		wantErr      error
+	}{
+		{name: "app a", bid: "com.example.a", wantPassword: "secret-a"},
+		{name: "app b", bid: "com.example.b", wantPassword: "secret-b"},
+		{name: "unknown app", bid: "com.example.c", wantErr: ErrUnknownBundle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPassword = ""
+			body, _ := json.Marshal(NotificationV1{Bid: tt.bid, UnifiedReceipt: UnifiedReceipt{LatestReceipt: "receipt"}})
+			err := r.OnNotificationV1(context.Background(), body, nil, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OnNotificationV1() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotPassword != tt.wantPassword {
+				t.Errorf("OnNotificationV1() password = %v, want %v", gotPassword, tt.wantPassword)
+			}
+		})
+	}
+}
+
+func TestRegistry_Reload(t *testing.T) {
+	r, err := NewRegistry(RegistryConfig{Apps: []Config{{BundleID: "com.example.a", PSW: "v1"}, {BundleID: "com.example.b"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := r.Client("com.example.b")
+
+	err = r.Reload(RegistryConfig{Apps: []Config{{BundleID: "com.example.a", PSW: "v2"}, {BundleID: "com.example.b"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := r.Client("com.example.a")
+	if a.Config().PSW != "v2" {
+		t.Errorf("Reload() psw = %v, want v2", a.Config().PSW)
+	}
+	if after, _ := r.Client("com.example.b"); after != before {
+		t.Error("Reload() should keep the client of an unchanged app")
+	}
+
+	if err = r.Reload(RegistryConfig{Apps: []Config{{BundleID: "com.example.a"}, {BundleID: "com.example.a"}}}); err == nil {
+		t.Error("Reload() with duplicated bundle id should fail")
+	}
+	if a, _ := r.Client("com.example.a"); a.Config().PSW != "v2" {
+		t.Error("Reload() should leave the registry untouched after a failed reload")
+	}
+}
+
+func TestRegistry_APIKey(t *testing.T) {
+	r, err := LoadRegistry([]byte(`
+apps:
+  - bundle_id: com.example.a
+    key_id: 2X9R4HXF34
+    issuer_id: 57246542-96fe-1a63-e053-0824d011072a
+    private_key: private-key-a
+  - bundle_id: com.example.b
+    psw: secret-b
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		bundleID string
+		want     APIKey
+		wantErr  error
+	}{
+		{name: "configured", bundleID: "com.example.a", want: APIKey{KeyID: "2X9R4HXF34", IssuerID: "57246542-96fe-1a63-e053-0824d011072a", PrivateKey: "private-key-a"}},
+		{name: "not configured", bundleID: "com.example.b", wantErr: ErrNoAPIKey},
+		{name: "unknown app", bundleID: "com.example.c", wantErr: ErrUnknownBundle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.APIKey(tt.bundleID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("APIKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("APIKey() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,19 @@ type Config struct {
 	BundleID string `yaml:"bundle_id"`
 	PSW      string `yaml:"psw"`
 	RootCA   string `yaml:"root_ca"`
+
+	// App Store Connect API key
+	// https://developer.apple.com/documentation/appstoreserverapi/creating_api_keys_to_use_with_the_app_store_server_api
+	KeyID      string `yaml:"key_id"`
+	IssuerID   string `yaml:"issuer_id"`
+	PrivateKey string `yaml:"private_key"`
+}
+
+// APIKey is the App Store Connect API key of an app, PrivateKey is the content of the .p8 file.
+type APIKey struct {
+	KeyID      string
+	IssuerID   string
+	PrivateKey string
 }
 
 // Receipt is the receipt for an in-app purchase.
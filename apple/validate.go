@@ -4,6 +4,7 @@ import (
 	"crypto/sha1"
 	"crypto/x509"
 	"encoding/asn1"
+	"encoding/base64"
 	"time"
 
 	"github.com/fullsailor/pkcs7"
@@ -27,6 +28,27 @@ func ParseReceipt(root *x509.Certificate, data []byte) (Receipts, error) {
 	return parsePKCS(pkcs)
 }
 
+// ParseBundleID reads the bundle id of a base64 receipt without verifying it,
+// it is used to select the config the receipt should be verified with.
+func ParseBundleID(receipt string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(receipt)
+	if err != nil {
+		return "", err
+	}
+
+	pkcs, err := pkcs7.Parse(data)
+	if err != nil {
+		return "", err
+	}
+
+	receipts, err := parsePKCS(pkcs)
+	if err != nil {
+		return "", err
+	}
+
+	return receipts.BundleID, nil
+}
+
 // Verify  the receipts with given guid.
 // TestReceiptValidate shows how to get GUID from string.
 // Check https://developer.apple.com/library/ios/releasenotes/General/ValidateAppStoreReceipt/Chapters/ValidateLocally.html#//apple_ref/doc/uid/TP40010573-CH1-SW5
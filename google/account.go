@@ -0,0 +1,69 @@
+package google
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+
+	"github.com/linhoi/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+// ObfuscateAccountID returns the value the app should set as obfuscatedAccountId in BillingFlowParams.
+// The account id is hashed with sha256 when an account id salt is configured.
+// https://developer.android.com/google/play/billing/developer-payload#attribute
+func (c *Client) ObfuscateAccountID(accountID string) string {
+	if c.accountIDSalt == "" {
+		return accountID
+	}
+
+	sum := sha256.Sum256([]byte(c.accountIDSalt + accountID))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetPurchaseByReceiptForAccount gets the purchase and makes sure it was bought by accountID.
+func (c *Client) GetPurchaseByReceiptForAccount(ctx context.Context, receipt Receipt, accountID string) (*androidpublisher.ProductPurchase, error) {
+	res, err := c.GetPurchaseByReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.checkAccount(accountID, res.ObfuscatedExternalAccountId, res.ObfuscatedExternalProfileId); err != nil {
+		log.L(ctx).Warn("google play purchase account check failed", zap.Error(err), zap.String("order_id", res.OrderId))
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetSubscriptionByReceiptForAccount gets the subscription and makes sure it was bought by accountID.
+func (c *Client) GetSubscriptionByReceiptForAccount(ctx context.Context, receipt Receipt, accountID string) (*androidpublisher.SubscriptionPurchase, error) {
+	res, err := c.GetSubscriptionByReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.checkAccount(accountID, res.ObfuscatedExternalAccountId, res.ObfuscatedExternalProfileId); err != nil {
+		log.L(ctx).Warn("google play subscription account check failed", zap.Error(err), zap.String("order_id", res.OrderId))
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) checkAccount(accountID string, obfuscatedAccountID, obfuscatedProfileID string) error {
+	if obfuscatedAccountID == "" && obfuscatedProfileID == "" {
+		return ErrAccountNotBound
+	}
+
+	want := []byte(c.ObfuscateAccountID(accountID))
+	for _, got := range []string{obfuscatedAccountID, obfuscatedProfileID} {
+		if got != "" && subtle.ConstantTimeCompare([]byte(got), want) == 1 {
+			return nil
+		}
+	}
+
+	return ErrAccountMismatch
+}
@@ -0,0 +1,82 @@
+package google
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClient_GetPurchaseByReceiptForAccount(t *testing.T) {
+	receipt := Receipt{ReceiptCore: ReceiptCore{PackageName: "com.example.app", ProductID: "coins_100", Token: "token"}}
+	tests := []struct {
+		name      string
+		salt      string
+		purchase  map[string]interface{}
+		accountID string
+		wantErr   error
+	}{
+		{
+			name:      "raw account id",
+			purchase:  map[string]interface{}{"obfuscatedExternalAccountId": "user-1"},
+			accountID: "user-1",
+		},
+		{
+			name: "salted account id",
+			salt: "salt",
+			// sha256("saltuser-1")
+			purchase:  map[string]interface{}{"obfuscatedExternalProfileId": "db62e91f67de4407261c734ef49c80812547147bfa4890e70c63763aac181852"},
+			accountID: "user-1",
+		},
+		{
+			name:      "replayed by another account",
+			purchase:  map[string]interface{}{"obfuscatedExternalAccountId": "user-1"},
+			accountID: "user-2",
+			wantErr:   ErrAccountMismatch,
+		},
+		{
+			name:      "not bound",
+			purchase:  map[string]interface{}{},
+			accountID: "user-1",
+			wantErr:   ErrAccountNotBound,
+		},
+		{
+			name:      "pending",
+			purchase:  map[string]interface{}{"obfuscatedExternalAccountId": "user-1", "purchaseState": 2},
+			accountID: "user-1",
+			wantErr:   ErrPurchasePending,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &testPublisher{routes: map[string]interface{}{
+				"GET /purchases/products/coins_100/tokens/token": tt.purchase,
+			}}
+			c := newTestClient(t, publisher)
+			c.accountIDSalt = tt.salt
+
+			res, err := c.GetPurchaseByReceiptForAccount(context.Background(), receipt, tt.accountID)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (res == nil) {
+				t.Errorf("GetPurchaseByReceiptForAccount() got = %v, error = %v, wantErr %v", res, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_ObfuscateAccountID(t *testing.T) {
+	tests := []struct {
+		name string
+		salt string
+		want string
+	}{
+		{name: "without salt", want: "user-1"},
+		{name: "with salt", salt: "salt", want: "db62e91f67de4407261c734ef49c80812547147bfa4890e70c63763aac181852"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{accountIDSalt: tt.salt}
+			if got := c.ObfuscateAccountID("user-1"); got != tt.want {
+				t.Errorf("ObfuscateAccountID() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,958 @@
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/linhoi/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/api/androidpublisher/v3"
+	"google.golang.org/api/googleapi"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// regionsVersion is the version of the available regions used by monetization subscriptions.
+	// https://developers.google.com/android-publisher/api-ref/rest/v3/RegionsVersion
+	regionsVersion = "2022/02"
+
+	monetizationSubscriptionsPath = "androidpublisher/v3/applications/%s/subscriptions"
+
+	InAppProductStatusActive   = "active"
+	InAppProductStatusInactive = "inactive"
+
+	PurchaseTypeManagedUser = "managedUser"
+	// PurchaseTypeSubscription is the legacy subscription sku, managed by monetization subscriptions now.
+	PurchaseTypeSubscription = "subscription"
+
+	BasePlanStateDraft    = "DRAFT"
+	BasePlanStateActive   = "ACTIVE"
+	BasePlanStateInactive = "INACTIVE"
+
+	OfferStateDraft    = "DRAFT"
+	OfferStateActive   = "ACTIVE"
+	OfferStateInactive = "INACTIVE"
+)
+
+// Catalog is the declarative description of the in-app products and subscriptions of an app.
+// Products, subscriptions, base plans and offers that exist in Play Console but not in the catalog
+// are only deactivated (or archived) when Prune is set, they are never deleted.
+type Catalog struct {
+	PackageName   string         `yaml:"package_name"`
+	Prune         bool           `yaml:"prune"`
+	Products      []Product      `yaml:"products"`
+	Subscriptions []Subscription `yaml:"subscriptions"`
+}
+
+// LoadCatalog parses a yaml catalog.
+func LoadCatalog(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	if c.PackageName == "" {
+		return nil, fmt.Errorf("catalog package_name is empty")
+	}
+
+	for i := range c.Products {
+		if c.Products[i].SKU == "" {
+			return nil, fmt.Errorf("catalog product %d has no sku", i)
+		}
+	}
+
+	for i := range c.Subscriptions {
+		if c.Subscriptions[i].ProductID == "" {
+			return nil, fmt.Errorf("catalog subscription %d has no product_id", i)
+		}
+	}
+
+	return &c, nil
+}
+
+// Product is a managed in-app product.
+type Product struct {
+	SKU             string                    `yaml:"sku"`
+	Status          string                    `yaml:"status"`
+	PurchaseType    string                    `yaml:"purchase_type"`
+	DefaultLanguage string                    `yaml:"default_language"`
+	DefaultPrice    ProductPrice              `yaml:"default_price"`
+	Prices          map[string]ProductPrice   `yaml:"prices"`
+	Listings        map[string]ProductListing `yaml:"listings"`
+}
+
+type ProductPrice struct {
+	Currency    string `yaml:"currency"`
+	PriceMicros string `yaml:"price_micros"`
+}
+
+type ProductListing struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Benefits    []string `yaml:"benefits"`
+}
+
+func (p Product) normalize() Product {
+	if p.Status == "" {
+		p.Status = InAppProductStatusActive
+	}
+	if p.PurchaseType == "" {
+		p.PurchaseType = PurchaseTypeManagedUser
+	}
+	if len(p.Prices) == 0 {
+		p.Prices = nil
+	}
+	if len(p.Listings) == 0 {
+		p.Listings = nil
+	}
+	for k, l := range p.Listings {
+		if len(l.Benefits) == 0 {
+			l.Benefits = nil
+			p.Listings[k] = l
+		}
+	}
+
+	return p
+}
+
+func (p Product) toInAppProduct(packageName string) *androidpublisher.InAppProduct {
+	p = p.normalize()
+	product := &androidpublisher.InAppProduct{
+		PackageName:     packageName,
+		Sku:             p.SKU,
+		Status:          p.Status,
+		PurchaseType:    p.PurchaseType,
+		DefaultLanguage: p.DefaultLanguage,
+		DefaultPrice:    &androidpublisher.Price{Currency: p.DefaultPrice.Currency, PriceMicros: p.DefaultPrice.PriceMicros},
+	}
+
+	if len(p.Prices) > 0 {
+		product.Prices = make(map[string]androidpublisher.Price, len(p.Prices))
+		for region, price := range p.Prices {
+			product.Prices[region] = androidpublisher.Price{Currency: price.Currency, PriceMicros: price.PriceMicros}
+		}
+	}
+
+	if len(p.Listings) > 0 {
+		product.Listings = make(map[string]androidpublisher.InAppProductListing, len(p.Listings))
+		for language, listing := range p.Listings {
+			product.Listings[language] = androidpublisher.InAppProductListing{
+				Title:       listing.Title,
+				Description: listing.Description,
+				Benefits:    listing.Benefits,
+			}
+		}
+	}
+
+	return product
+}
+
+func productFromInAppProduct(product *androidpublisher.InAppProduct) Product {
+	p := Product{
+		SKU:             product.Sku,
+		Status:          product.Status,
+		PurchaseType:    product.PurchaseType,
+		DefaultLanguage: product.DefaultLanguage,
+	}
+	if product.DefaultPrice != nil {
+		p.DefaultPrice = ProductPrice{Currency: product.DefaultPrice.Currency, PriceMicros: product.DefaultPrice.PriceMicros}
+	}
+
+	if len(product.Prices) > 0 {
+		p.Prices = make(map[string]ProductPrice, len(product.Prices))
+		for region, price := range product.Prices {
+			p.Prices[region] = ProductPrice{Currency: price.Currency, PriceMicros: price.PriceMicros}
+		}
+	}
+
+	if len(product.Listings) > 0 {
+		p.Listings = make(map[string]ProductListing, len(product.Listings))
+		for language, listing := range product.Listings {
+			p.Listings[language] = ProductListing{
+				Title:       listing.Title,
+				Description: listing.Description,
+				Benefits:    listing.Benefits,
+			}
+		}
+	}
+
+	return p.normalize()
+}
+
+// Subscription is a monetization subscription with its base plans.
+// https://developers.google.com/android-publisher/api-ref/rest/v3/monetization.subscriptions
+type Subscription struct {
+	PackageName string                `json:"packageName,omitempty" yaml:"-"`
+	ProductID   string                `json:"productId" yaml:"product_id"`
+	Archived    bool                  `json:"archived,omitempty" yaml:"-"`
+	Listings    []SubscriptionListing `json:"listings,omitempty" yaml:"listings"`
+	BasePlans   []BasePlan            `json:"basePlans,omitempty" yaml:"base_plans"`
+}
+
+// payload returns the subscription to send, states are output only and managed by
+// ActivateBasePlan and DeactivateBasePlan.
+func (s *Subscription) payload(packageName string) *Subscription {
+	p := *s
+	p.PackageName = packageName
+	p.Archived = false
+	p.BasePlans = make([]BasePlan, 0, len(s.BasePlans))
+	for _, b := range s.BasePlans {
+		b.State = ""
+		p.BasePlans = append(p.BasePlans, b)
+	}
+
+	return &p
+}
+
+type SubscriptionListing struct {
+	LanguageCode string   `json:"languageCode" yaml:"language_code"`
+	Title        string   `json:"title" yaml:"title"`
+	Description  string   `json:"description,omitempty" yaml:"description"`
+	Benefits     []string `json:"benefits,omitempty" yaml:"benefits"`
+}
+
+// BasePlan state is managed by ActivateBasePlan and DeactivateBasePlan,
+// offers are managed through the offers endpoints.
+type BasePlan struct {
+	BasePlanID      string                   `json:"basePlanId" yaml:"base_plan_id"`
+	State           string                   `json:"state,omitempty" yaml:"state"`
+	AutoRenewing    *AutoRenewingBasePlan    `json:"autoRenewingBasePlanType,omitempty" yaml:"auto_renewing"`
+	Prepaid         *PrepaidBasePlan         `json:"prepaidBasePlanType,omitempty" yaml:"prepaid"`
+	RegionalConfigs []RegionalBasePlanConfig `json:"regionalConfigs,omitempty" yaml:"regional_configs"`
+	OfferTags       []OfferTag               `json:"offerTags,omitempty" yaml:"offer_tags"`
+	Offers          []SubscriptionOffer      `json:"-" yaml:"offers"`
+}
+
+type AutoRenewingBasePlan struct {
+	BillingPeriodDuration string `json:"billingPeriodDuration" yaml:"billing_period_duration"`
+	GracePeriodDuration   string `json:"gracePeriodDuration,omitempty" yaml:"grace_period_duration"`
+	ResubscribeState      string `json:"resubscribeState,omitempty" yaml:"resubscribe_state"`
+	ProrationMode         string `json:"prorationMode,omitempty" yaml:"proration_mode"`
+	LegacyCompatible      bool   `json:"legacyCompatible,omitempty" yaml:"legacy_compatible"`
+}
+
+type PrepaidBasePlan struct {
+	BillingPeriodDuration string `json:"billingPeriodDuration" yaml:"billing_period_duration"`
+	TimeExtension         string `json:"timeExtension,omitempty" yaml:"time_extension"`
+}
+
+type RegionalBasePlanConfig struct {
+	RegionCode                string `json:"regionCode" yaml:"region_code"`
+	NewSubscriberAvailability bool   `json:"newSubscriberAvailability,omitempty" yaml:"new_subscriber_availability"`
+	Price                     *Money `json:"price,omitempty" yaml:"price"`
+}
+
+type OfferTag struct {
+	Tag string `json:"tag" yaml:"tag"`
+}
+
+// Money https://developers.google.com/android-publisher/api-ref/rest/v3/Money
+type Money struct {
+	CurrencyCode string `json:"currencyCode" yaml:"currency_code"`
+	Units        string `json:"units,omitempty" yaml:"units"`
+	Nanos        int64  `json:"nanos,omitempty" yaml:"nanos"`
+}
+
+// SubscriptionOffer https://developers.google.com/android-publisher/api-ref/rest/v3/monetization.subscriptions.basePlans.offers
+type SubscriptionOffer struct {
+	PackageName     string                `json:"packageName,omitempty" yaml:"-"`
+	ProductID       string                `json:"productId,omitempty" yaml:"-"`
+	BasePlanID      string                `json:"basePlanId,omitempty" yaml:"-"`
+	OfferID         string                `json:"offerId" yaml:"offer_id"`
+	State           string                `json:"state,omitempty" yaml:"state"`
+	Phases          []OfferPhase          `json:"phases,omitempty" yaml:"phases"`
+	RegionalConfigs []RegionalOfferConfig `json:"regionalConfigs,omitempty" yaml:"regional_configs"`
+	OfferTags       []OfferTag            `json:"offerTags,omitempty" yaml:"offer_tags"`
+}
+
+func (o *SubscriptionOffer) payload(packageName, productID, basePlanID string) *SubscriptionOffer {
+	p := *o
+	p.PackageName = packageName
+	p.ProductID = productID
+	p.BasePlanID = basePlanID
+	p.State = ""
+
+	return &p
+}
+
+type OfferPhase struct {
+	RecurrenceCount int64                      `json:"recurrenceCount" yaml:"recurrence_count"`
+	Duration        string                     `json:"duration" yaml:"duration"`
+	RegionalConfigs []RegionalOfferPhaseConfig `json:"regionalConfigs,omitempty" yaml:"regional_configs"`
+}
+
+type RegionalOfferPhaseConfig struct {
+	RegionCode       string    `json:"regionCode" yaml:"region_code"`
+	Price            *Money    `json:"price,omitempty" yaml:"price"`
+	AbsoluteDiscount *Money    `json:"absoluteDiscount,omitempty" yaml:"absolute_discount"`
+	RelativeDiscount float64   `json:"relativeDiscount,omitempty" yaml:"relative_discount"`
+	Free             *struct{} `json:"free,omitempty" yaml:"free"`
+}
+
+type RegionalOfferConfig struct {
+	RegionCode                string `json:"regionCode" yaml:"region_code"`
+	NewSubscriberAvailability bool   `json:"newSubscriberAvailability,omitempty" yaml:"new_subscriber_availability"`
+}
+
+type listSubscriptionsResp struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+	NextPageToken string          `json:"nextPageToken"`
+}
+
+type listSubscriptionOffersResp struct {
+	SubscriptionOffers []*SubscriptionOffer `json:"subscriptionOffers"`
+	NextPageToken      string               `json:"nextPageToken"`
+}
+
+// ListInAppProducts list all in-app products of the package.
+func (c *Client) ListInAppProducts(ctx context.Context, packageName string) ([]*androidpublisher.InAppProduct, error) {
+	var products []*androidpublisher.InAppProduct
+	var token string
+	for {
+		req := c.googlePublisher.Inappproducts.List(packageName).Context(ctx)
+		if token != "" {
+			req = req.Token(token)
+		}
+
+		res, err := req.Do()
+		if err != nil {
+			log.L(ctx).Warn("google play list in-app products failed", zap.Error(err), zap.String("package_name", packageName))
+			return nil, classifyError(err)
+		}
+		products = append(products, res.Inappproduct...)
+
+		if res.TokenPagination == nil || res.TokenPagination.NextPageToken == "" {
+			break
+		}
+		token = res.TokenPagination.NextPageToken
+	}
+
+	return products, nil
+}
+
+func (c *Client) CreateInAppProduct(ctx context.Context, packageName string, product *androidpublisher.InAppProduct) (*androidpublisher.InAppProduct, error) {
+	res, err := c.googlePublisher.Inappproducts.Insert(packageName, product).Context(ctx).Do()
+	if err != nil {
+		log.L(ctx).Warn("google play create in-app product failed", zap.Error(err), zap.String("sku", product.Sku))
+		return nil, classifyError(err)
+	}
+
+	return res, nil
+}
+
+func (c *Client) UpdateInAppProduct(ctx context.Context, packageName string, product *androidpublisher.InAppProduct) (*androidpublisher.InAppProduct, error) {
+	res, err := c.googlePublisher.Inappproducts.Update(packageName, product.Sku, product).Context(ctx).Do()
+	if err != nil {
+		log.L(ctx).Warn("google play update in-app product failed", zap.Error(err), zap.String("sku", product.Sku))
+		return nil, classifyError(err)
+	}
+
+	return res, nil
+}
+
+// DeactivateInAppProduct marks the product inactive, the product can not be deleted once purchased.
+func (c *Client) DeactivateInAppProduct(ctx context.Context, packageName string, sku string) error {
+	product, err := c.googlePublisher.Inappproducts.Get(packageName, sku).Context(ctx).Do()
+	if err != nil {
+		log.L(ctx).Warn("google play get in-app product failed", zap.Error(err), zap.String("sku", sku))
+		return classifyError(err)
+	}
+
+	if product.Status == InAppProductStatusInactive {
+		return nil
+	}
+
+	product.Status = InAppProductStatusInactive
+	_, err = c.UpdateInAppProduct(ctx, packageName, product)
+	return err
+}
+
+// ListSubscriptions list all subscriptions of the package, archived subscriptions are included.
+func (c *Client) ListSubscriptions(ctx context.Context, packageName string) ([]*Subscription, error) {
+	var subscriptions []*Subscription
+	query := url.Values{"showArchived": {"true"}}
+	for {
+		var res listSubscriptionsResp
+		err := c.doMonetization(ctx, http.MethodGet, fmt.Sprintf(monetizationSubscriptionsPath, packageName), query, nil, &res)
+		if err != nil {
+			log.L(ctx).Warn("google play list subscriptions failed", zap.Error(err), zap.String("package_name", packageName))
+			return nil, classifyError(err)
+		}
+		subscriptions = append(subscriptions, res.Subscriptions...)
+
+		if res.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", res.NextPageToken)
+	}
+
+	return subscriptions, nil
+}
+
+func (c *Client) CreateSubscription(ctx context.Context, packageName string, subscription *Subscription) (*Subscription, error) {
+	query := url.Values{"productId": {subscription.ProductID}, "regionsVersion.version": {regionsVersion}}
+	var res Subscription
+	err := c.doMonetization(ctx, http.MethodPost, fmt.Sprintf(monetizationSubscriptionsPath, packageName), query, subscription.payload(packageName), &res)
+	if err != nil {
+		log.L(ctx).Warn("google play create subscription failed", zap.Error(err), zap.String("product_id", subscription.ProductID))
+		return nil, classifyError(err)
+	}
+
+	return &res, nil
+}
+
+// UpdateSubscription updates the listings and base plans of the subscription.
+func (c *Client) UpdateSubscription(ctx context.Context, packageName string, subscription *Subscription) (*Subscription, error) {
+	query := url.Values{"updateMask": {"listings,basePlans"}, "regionsVersion.version": {regionsVersion}}
+	path := fmt.Sprintf(monetizationSubscriptionsPath, packageName) + "/" + subscription.ProductID
+	var res Subscription
+	err := c.doMonetization(ctx, http.MethodPatch, path, query, subscription.payload(packageName), &res)
+	if err != nil {
+		log.L(ctx).Warn("google play update subscription failed", zap.Error(err), zap.String("product_id", subscription.ProductID))
+		return nil, classifyError(err)
+	}
+
+	return &res, nil
+}
+
+// ArchiveSubscription archives the subscription, archived subscriptions can not be purchased.
+func (c *Client) ArchiveSubscription(ctx context.Context, packageName string, productID string) error {
+	path := fmt.Sprintf(monetizationSubscriptionsPath, packageName) + "/" + productID + ":archive"
+	err := c.doMonetization(ctx, http.MethodPost, path, nil, struct{}{}, nil)
+	if err != nil {
+		log.L(ctx).Warn("google play archive subscription failed", zap.Error(err), zap.String("product_id", productID))
+		return classifyError(err)
+	}
+
+	return nil
+}
+
+func (c *Client) ActivateBasePlan(ctx context.Context, packageName, productID, basePlanID string) error {
+	return c.basePlanAction(ctx, packageName, productID, basePlanID, "activate")
+}
+
+func (c *Client) DeactivateBasePlan(ctx context.Context, packageName, productID, basePlanID string) error {
+	return c.basePlanAction(ctx, packageName, productID, basePlanID, "deactivate")
+}
+
+func (c *Client) basePlanAction(ctx context.Context, packageName, productID, basePlanID, action string) error {
+	path := fmt.Sprintf(monetizationSubscriptionsPath, packageName) + "/" + productID + "/basePlans/" + basePlanID + ":" + action
+	body := map[string]string{"packageName": packageName, "productId": productID, "basePlanId": basePlanID}
+	err := c.doMonetization(ctx, http.MethodPost, path, nil, body, nil)
+	if err != nil {
+		log.L(ctx).Warn("google play base plan "+action+" failed", zap.Error(err), zap.String("product_id", productID), zap.String("base_plan_id", basePlanID))
+		return classifyError(err)
+	}
+
+	return nil
+}
+
+func (c *Client) ListOffers(ctx context.Context, packageName, productID, basePlanID string) ([]*SubscriptionOffer, error) {
+	var offers []*SubscriptionOffer
+	path := fmt.Sprintf(monetizationSubscriptionsPath, packageName) + "/" + productID + "/basePlans/" + basePlanID + "/offers"
+	query := url.Values{}
+	for {
+		var res listSubscriptionOffersResp
+		err := c.doMonetization(ctx, http.MethodGet, path, query, nil, &res)
+		if err != nil {
+			log.L(ctx).Warn("google play list offers failed", zap.Error(err), zap.String("product_id", productID), zap.String("base_plan_id", basePlanID))
+			return nil, classifyError(err)
+		}
+		offers = append(offers, res.SubscriptionOffers...)
+
+		if res.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", res.NextPageToken)
+	}
+
+	return offers, nil
+}
+
+func (c *Client) CreateOffer(ctx context.Context, packageName, productID, basePlanID string, offer *SubscriptionOffer) (*SubscriptionOffer, error) {
+	path := fmt.Sprintf(monetizationSubscriptionsPath, packageName) + "/" + productID + "/basePlans/" + basePlanID + "/offers"
+	query := url.Values{"offerId": {offer.OfferID}, "regionsVersion.version": {regionsVersion}}
+	var res SubscriptionOffer
+	err := c.doMonetization(ctx, http.MethodPost, path, query, offer.payload(packageName, productID, basePlanID), &res)
+	if err != nil {
+		log.L(ctx).Warn("google play create offer failed", zap.Error(err), zap.String("product_id", productID), zap.String("offer_id", offer.OfferID))
+		return nil, classifyError(err)
+	}
+
+	return &res, nil
+}
+
+func (c *Client) UpdateOffer(ctx context.Context, packageName, productID, basePlanID string, offer *SubscriptionOffer) (*SubscriptionOffer, error) {
+	path := fmt.Sprintf(monetizationSubscriptionsPath, packageName) + "/" + productID + "/basePlans/" + basePlanID + "/offers/" + offer.OfferID
+	query := url.Values{"updateMask": {"phases,regionalConfigs,offerTags"}, "regionsVersion.version": {regionsVersion}}
+	var res SubscriptionOffer
+	err := c.doMonetization(ctx, http.MethodPatch, path, query, offer.payload(packageName, productID, basePlanID), &res)
+	if err != nil {
+		log.L(ctx).Warn("google play update offer failed", zap.Error(err), zap.String("product_id", productID), zap.String("offer_id", offer.OfferID))
+		return nil, classifyError(err)
+	}
+
+	return &res, nil
+}
+
+func (c *Client) ActivateOffer(ctx context.Context, packageName, productID, basePlanID, offerID string) error {
+	return c.offerAction(ctx, packageName, productID, basePlanID, offerID, "activate")
+}
+
+func (c *Client) DeactivateOffer(ctx context.Context, packageName, productID, basePlanID, offerID string) error {
+	return c.offerAction(ctx, packageName, productID, basePlanID, offerID, "deactivate")
+}
+
+func (c *Client) offerAction(ctx context.Context, packageName, productID, basePlanID, offerID, action string) error {
+	path := fmt.Sprintf(monetizationSubscriptionsPath, packageName) + "/" + productID + "/basePlans/" + basePlanID + "/offers/" + offerID + ":" + action
+	body := map[string]string{"packageName": packageName, "productId": productID, "basePlanId": basePlanID, "offerId": offerID}
+	err := c.doMonetization(ctx, http.MethodPost, path, nil, body, nil)
+	if err != nil {
+		log.L(ctx).Warn("google play offer "+action+" failed", zap.Error(err), zap.String("product_id", productID), zap.String("offer_id", offerID))
+		return classifyError(err)
+	}
+
+	return nil
+}
+
+// doMonetization calls the monetization endpoints which are not generated in androidpublisher yet.
+func (c *Client) doMonetization(ctx context.Context, method, path string, query url.Values, body interface{}, resp interface{}) error {
+	u := c.googlePublisher.BasePath + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.publisherClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err = googleapi.CheckResponse(res); err != nil {
+		return err
+	}
+
+	if resp == nil {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, resp)
+}
+
+type CatalogAction string
+
+const (
+	CatalogActionCreate     CatalogAction = "create"
+	CatalogActionUpdate     CatalogAction = "update"
+	CatalogActionActivate   CatalogAction = "activate"
+	CatalogActionDeactivate CatalogAction = "deactivate"
+)
+
+type CatalogKind string
+
+const (
+	CatalogKindProduct      CatalogKind = "product"
+	CatalogKindSubscription CatalogKind = "subscription"
+	CatalogKindBasePlan     CatalogKind = "base_plan"
+	CatalogKindOffer        CatalogKind = "offer"
+)
+
+// CatalogChange is a single change DiffCatalog found between the catalog and Play Console.
+type CatalogChange struct {
+	Action      CatalogAction
+	Kind        CatalogKind
+	PackageName string
+	ProductID   string
+	BasePlanID  string
+	OfferID     string
+
+	Product      *Product
+	Subscription *Subscription
+	Offer        *SubscriptionOffer
+}
+
+func (c CatalogChange) String() string {
+	id := c.ProductID
+	if c.BasePlanID != "" {
+		id += "/" + c.BasePlanID
+	}
+	if c.OfferID != "" {
+		id += "/" + c.OfferID
+	}
+
+	return fmt.Sprintf("%s %s %s", c.Action, c.Kind, id)
+}
+
+// DiffCatalog compares the catalog with Play Console and returns the changes ApplyCatalog would push.
+// It fails if a subscription of the catalog is archived in Play Console, as it can not be updated any more.
+func (c *Client) DiffCatalog(ctx context.Context, catalog *Catalog) ([]CatalogChange, error) {
+	products, err := c.ListInAppProducts(ctx, catalog.PackageName)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := c.ListSubscriptions(ctx, catalog.PackageName)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffProducts(catalog, products)
+
+	remoteSubscriptions := make(map[string]*Subscription, len(subscriptions))
+	for _, s := range subscriptions {
+		remoteSubscriptions[s.ProductID] = s
+	}
+
+	desired := make(map[string]bool, len(catalog.Subscriptions))
+	for i := range catalog.Subscriptions {
+		want := &catalog.Subscriptions[i]
+		desired[want.ProductID] = true
+
+		subscriptionChanges, err := c.diffSubscription(ctx, catalog.PackageName, want, remoteSubscriptions[want.ProductID], catalog.Prune)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, subscriptionChanges...)
+	}
+
+	if catalog.Prune {
+		for _, s := range subscriptions {
+			if !desired[s.ProductID] && !s.Archived {
+				changes = append(changes, CatalogChange{Action: CatalogActionDeactivate, Kind: CatalogKindSubscription, ProductID: s.ProductID})
+			}
+		}
+	}
+
+	for i := range changes {
+		changes[i].PackageName = catalog.PackageName
+	}
+
+	return changes, nil
+}
+
+// ApplyCatalog pushes the changes returned by DiffCatalog in order.
+// Nothing is applied if a change was diffed against another package than packageName.
+func (c *Client) ApplyCatalog(ctx context.Context, packageName string, changes []CatalogChange) error {
+	for _, change := range changes {
+		if change.PackageName != "" && change.PackageName != packageName {
+			return fmt.Errorf("%s: diffed for package %s, not %s", change, change.PackageName, packageName)
+		}
+	}
+
+	for _, change := range changes {
+		log.L(ctx).Info("google play apply catalog change", zap.String("change", change.String()))
+
+		var err error
+		switch change.Kind {
+		case CatalogKindProduct:
+			err = c.applyProductChange(ctx, packageName, change)
+		case CatalogKindSubscription:
+			err = c.applySubscriptionChange(ctx, packageName, change)
+		case CatalogKindBasePlan:
+			err = c.applyBasePlanChange(ctx, packageName, change)
+		case CatalogKindOffer:
+			err = c.applyOfferChange(ctx, packageName, change)
+		default:
+			err = fmt.Errorf("unknown catalog kind %q", change.Kind)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", change, err)
+		}
+	}
+
+	return nil
+}
+
+func diffProducts(catalog *Catalog, remote []*androidpublisher.InAppProduct) []CatalogChange {
+	var changes []CatalogChange
+	remoteProducts := make(map[string]Product, len(remote))
+	for _, p := range remote {
+		if p.PurchaseType == PurchaseTypeSubscription {
+			continue
+		}
+		remoteProducts[p.Sku] = productFromInAppProduct(p)
+	}
+
+	desired := make(map[string]bool, len(catalog.Products))
+	for i := range catalog.Products {
+		want := catalog.Products[i].normalize()
+		desired[want.SKU] = true
+
+		got, ok := remoteProducts[want.SKU]
+		if want.Prices == nil {
+			// Play fills in the regional prices from the default price, they only differ if the catalog sets them
+			got.Prices = nil
+		}
+		switch {
+		case !ok:
+			changes = append(changes, CatalogChange{Action: CatalogActionCreate, Kind: CatalogKindProduct, ProductID: want.SKU, Product: &want})
+		case !reflect.DeepEqual(got, want):
+			changes = append(changes, CatalogChange{Action: CatalogActionUpdate, Kind: CatalogKindProduct, ProductID: want.SKU, Product: &want})
+		}
+	}
+
+	if catalog.Prune {
+		for _, p := range remote {
+			if !desired[p.Sku] && p.PurchaseType != PurchaseTypeSubscription && p.Status != InAppProductStatusInactive {
+				changes = append(changes, CatalogChange{Action: CatalogActionDeactivate, Kind: CatalogKindProduct, ProductID: p.Sku})
+			}
+		}
+	}
+
+	return changes
+}
+
+func (c *Client) diffSubscription(ctx context.Context, packageName string, want *Subscription, got *Subscription, prune bool) ([]CatalogChange, error) {
+	if got != nil && got.Archived {
+		// archived subscriptions can neither be updated nor restored, the product id can not be used again
+		return nil, fmt.Errorf("catalog subscription %s is archived in play console, remove it from the catalog", want.ProductID)
+	}
+
+	var changes []CatalogChange
+	switch {
+	case got == nil:
+		changes = append(changes, CatalogChange{Action: CatalogActionCreate, Kind: CatalogKindSubscription, ProductID: want.ProductID, Subscription: want})
+	case !reflect.DeepEqual(comparableSubscription(got), comparableSubscription(want)):
+		changes = append(changes, CatalogChange{Action: CatalogActionUpdate, Kind: CatalogKindSubscription, ProductID: want.ProductID, Subscription: want})
+	}
+
+	remoteBasePlans := make(map[string]BasePlan)
+	if got != nil {
+		for _, b := range got.BasePlans {
+			remoteBasePlans[b.BasePlanID] = b
+		}
+	}
+
+	desired := make(map[string]bool, len(want.BasePlans))
+	for _, basePlan := range want.BasePlans {
+		desired[basePlan.BasePlanID] = true
+		remote, exist := remoteBasePlans[basePlan.BasePlanID]
+		if action, ok := stateChange(basePlan.State, remote.State); ok {
+			changes = append(changes, CatalogChange{Action: action, Kind: CatalogKindBasePlan, ProductID: want.ProductID, BasePlanID: basePlan.BasePlanID})
+		}
+
+		var remoteOffers []*SubscriptionOffer
+		if exist {
+			offers, err := c.ListOffers(ctx, packageName, want.ProductID, basePlan.BasePlanID)
+			if err != nil {
+				return nil, err
+			}
+			remoteOffers = offers
+		}
+		changes = append(changes, diffOffers(want.ProductID, basePlan, remoteOffers, prune)...)
+	}
+
+	// the offers of a deactivated base plan are not available either
+	if prune && got != nil {
+		for _, b := range got.BasePlans {
+			if !desired[b.BasePlanID] && b.State == BasePlanStateActive {
+				changes = append(changes, CatalogChange{Action: CatalogActionDeactivate, Kind: CatalogKindBasePlan, ProductID: want.ProductID, BasePlanID: b.BasePlanID})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+func diffOffers(productID string, basePlan BasePlan, remote []*SubscriptionOffer, prune bool) []CatalogChange {
+	var changes []CatalogChange
+	remoteOffers := make(map[string]*SubscriptionOffer, len(remote))
+	for _, o := range remote {
+		remoteOffers[o.OfferID] = o
+	}
+
+	for i := range basePlan.Offers {
+		want := &basePlan.Offers[i]
+		change := CatalogChange{Kind: CatalogKindOffer, ProductID: productID, BasePlanID: basePlan.BasePlanID, OfferID: want.OfferID, Offer: want}
+
+		got, ok := remoteOffers[want.OfferID]
+		switch {
+		case !ok:
+			change.Action = CatalogActionCreate
+			changes = append(changes, change)
+		case !reflect.DeepEqual(comparableOffer(got), comparableOffer(want)):
+			change.Action = CatalogActionUpdate
+			changes = append(changes, change)
+		}
+
+		var gotState string
+		if got != nil {
+			gotState = got.State
+		}
+		if action, ok := stateChange(want.State, gotState); ok {
+			changes = append(changes, CatalogChange{Action: action, Kind: CatalogKindOffer, ProductID: productID, BasePlanID: basePlan.BasePlanID, OfferID: want.OfferID})
+		}
+	}
+
+	if prune {
+		desired := make(map[string]bool, len(basePlan.Offers))
+		for _, o := range basePlan.Offers {
+			desired[o.OfferID] = true
+		}
+		for _, o := range remote {
+			if !desired[o.OfferID] && o.State == OfferStateActive {
+				changes = append(changes, CatalogChange{Action: CatalogActionDeactivate, Kind: CatalogKindOffer, ProductID: productID, BasePlanID: basePlan.BasePlanID, OfferID: o.OfferID})
+			}
+		}
+	}
+
+	return changes
+}
+
+// stateChange returns the action needed to move a base plan or offer from got to want.
+// An empty want state leaves the state as it is.
+func stateChange(want, got string) (CatalogAction, bool) {
+	want = strings.ToUpper(want)
+	switch {
+	case want == BasePlanStateActive && got != BasePlanStateActive:
+		return CatalogActionActivate, true
+	case want == BasePlanStateInactive && got == BasePlanStateActive:
+		return CatalogActionDeactivate, true
+	}
+
+	return "", false
+}
+
+// comparableSubscription drops the fields managed by other endpoints or by Google.
+func comparableSubscription(s *Subscription) Subscription {
+	listings := append([]SubscriptionListing(nil), s.Listings...)
+	sort.Slice(listings, func(i, j int) bool { return listings[i].LanguageCode < listings[j].LanguageCode })
+	for i := range listings {
+		if len(listings[i].Benefits) == 0 {
+			listings[i].Benefits = nil
+		}
+	}
+
+	basePlans := make([]BasePlan, 0, len(s.BasePlans))
+	for _, b := range s.BasePlans {
+		b.State = ""
+		b.Offers = nil
+		b.RegionalConfigs = append([]RegionalBasePlanConfig(nil), b.RegionalConfigs...)
+		sort.Slice(b.RegionalConfigs, func(i, j int) bool { return b.RegionalConfigs[i].RegionCode < b.RegionalConfigs[j].RegionCode })
+		if len(b.RegionalConfigs) == 0 {
+			b.RegionalConfigs = nil
+		}
+		if len(b.OfferTags) == 0 {
+			b.OfferTags = nil
+		}
+		basePlans = append(basePlans, b)
+	}
+	sort.Slice(basePlans, func(i, j int) bool { return basePlans[i].BasePlanID < basePlans[j].BasePlanID })
+	if len(listings) == 0 {
+		listings = nil
+	}
+	if len(basePlans) == 0 {
+		basePlans = nil
+	}
+
+	return Subscription{ProductID: s.ProductID, Listings: listings, BasePlans: basePlans}
+}
+
+func comparableOffer(o *SubscriptionOffer) SubscriptionOffer {
+	offer := SubscriptionOffer{OfferID: o.OfferID, Phases: o.Phases, RegionalConfigs: o.RegionalConfigs, OfferTags: o.OfferTags}
+	offer.RegionalConfigs = append([]RegionalOfferConfig(nil), offer.RegionalConfigs...)
+	sort.Slice(offer.RegionalConfigs, func(i, j int) bool {
+		return offer.RegionalConfigs[i].RegionCode < offer.RegionalConfigs[j].RegionCode
+	})
+	if len(offer.Phases) == 0 {
+		offer.Phases = nil
+	}
+	if len(offer.RegionalConfigs) == 0 {
+		offer.RegionalConfigs = nil
+	}
+	if len(offer.OfferTags) == 0 {
+		offer.OfferTags = nil
+	}
+
+	return offer
+}
+
+func (c *Client) applyProductChange(ctx context.Context, packageName string, change CatalogChange) error {
+	switch change.Action {
+	case CatalogActionCreate:
+		_, err := c.CreateInAppProduct(ctx, packageName, change.Product.toInAppProduct(packageName))
+		return err
+	case CatalogActionUpdate:
+		_, err := c.UpdateInAppProduct(ctx, packageName, change.Product.toInAppProduct(packageName))
+		return err
+	case CatalogActionDeactivate:
+		return c.DeactivateInAppProduct(ctx, packageName, change.ProductID)
+	}
+
+	return fmt.Errorf("unsupported action %q", change.Action)
+}
+
+func (c *Client) applySubscriptionChange(ctx context.Context, packageName string, change CatalogChange) error {
+	switch change.Action {
+	case CatalogActionCreate:
+		_, err := c.CreateSubscription(ctx, packageName, change.Subscription)
+		return err
+	case CatalogActionUpdate:
+		_, err := c.UpdateSubscription(ctx, packageName, change.Subscription)
+		return err
+	case CatalogActionDeactivate:
+		return c.ArchiveSubscription(ctx, packageName, change.ProductID)
+	}
+
+	return fmt.Errorf("unsupported action %q", change.Action)
+}
+
+func (c *Client) applyBasePlanChange(ctx context.Context, packageName string, change CatalogChange) error {
+	switch change.Action {
+	case CatalogActionActivate:
+		return c.ActivateBasePlan(ctx, packageName, change.ProductID, change.BasePlanID)
+	case CatalogActionDeactivate:
+		return c.DeactivateBasePlan(ctx, packageName, change.ProductID, change.BasePlanID)
+	}
+
+	return fmt.Errorf("unsupported action %q", change.Action)
+}
+
+func (c *Client) applyOfferChange(ctx context.Context, packageName string, change CatalogChange) error {
+	switch change.Action {
+	case CatalogActionCreate:
+		_, err := c.CreateOffer(ctx, packageName, change.ProductID, change.BasePlanID, change.Offer)
+		return err
+	case CatalogActionUpdate:
+		_, err := c.UpdateOffer(ctx, packageName, change.ProductID, change.BasePlanID, change.Offer)
+		return err
+	case CatalogActionActivate:
+		return c.ActivateOffer(ctx, packageName, change.ProductID, change.BasePlanID, change.OfferID)
+	case CatalogActionDeactivate:
+		return c.DeactivateOffer(ctx, packageName, change.ProductID, change.BasePlanID, change.OfferID)
+	}
+
+	return fmt.Errorf("unsupported action %q", change.Action)
+}
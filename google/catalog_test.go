@@ -0,0 +1,214 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+const testCatalog = `
+package_name: com.example.app
+prune: true
+products:
+  - sku: coins_100
+    default_language: en-US
+    default_price: {currency: USD, price_micros: "990000"}
+    listings:
+      en-US: {title: 100 Coins, description: A bag of coins}
+  - sku: coins_500
+    default_language: en-US
+    default_price: {currency: USD, price_micros: "3990000"}
+    listings:
+      en-US: {title: 500 Coins, description: A chest of coins}
+subscriptions:
+  - product_id: premium
+    listings:
+      - {language_code: en-US, title: Premium}
+    base_plans:
+      - base_plan_id: monthly
+        state: active
+        auto_renewing: {billing_period_duration: P1M}
+        regional_configs:
+          - {region_code: US, new_subscriber_availability: true, price: {currency_code: USD, units: "9", nanos: 990000000}}
+        offers:
+          - offer_id: trial
+            state: active
+            phases:
+              - recurrence_count: 1
+                duration: P1W
+                regional_configs:
+                  - {region_code: US, free: {}}
+            regional_configs:
+              - {region_code: US, new_subscriber_availability: true}
+`
+
+// testPublisher is a fake of the android publisher endpoints used by the catalog.
+type testPublisher struct {
+	mu       sync.Mutex
+	requests []string
+	routes   map[string]interface{}
+}
+
+func (p *testPublisher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/androidpublisher/v3/applications/com.example.app")
+	p.mu.Lock()
+	p.requests = append(p.requests, r.Method+" "+path)
+	p.mu.Unlock()
+
+	resp, ok := p.routes[r.Method+" "+path]
+	if !ok {
+		resp = map[string]interface{}{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := newClient(context.Background(), nil, option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestClient_DiffCatalog(t *testing.T) {
+	publisher := &testPublisher{routes: map[string]interface{}{
+		"GET /inappproducts": map[string]interface{}{
+			"inappproduct": []map[string]interface{}{
+				{
+					"sku": "coins_100", "status": "active", "purchaseType": "managedUser", "defaultLanguage": "en-US",
+					"defaultPrice": map[string]string{"currency": "USD", "priceMicros": "990000"},
+					"prices":       map[string]interface{}{"JP": map[string]string{"currency": "JPY", "priceMicros": "150000000"}},
+					"listings":     map[string]interface{}{"en-US": map[string]string{"title": "100 Coins", "description": "A bag of coins"}},
+				},
+				{"sku": "coins_old", "status": "active", "purchaseType": "managedUser"},
+				{"sku": "legacy_sub", "status": "active", "purchaseType": "subscription"},
+			},
+		},
+		"GET /inappproducts/coins_old": map[string]interface{}{"sku": "coins_old", "status": "active", "purchaseType": "managedUser"},
+		"GET /subscriptions": map[string]interface{}{
+			"subscriptions": []map[string]interface{}{
+				{
+					"productId": "premium",
+					"listings":  []map[string]string{{"languageCode": "en-US", "title": "Premium"}},
+					"basePlans": []map[string]interface{}{{
+						"basePlanId":               "monthly",
+						"state":                    "DRAFT",
+						"autoRenewingBasePlanType": map[string]string{"billingPeriodDuration": "P1M"},
+						"regionalConfigs": []map[string]interface{}{
+							{"regionCode": "US", "newSubscriberAvailability": true, "price": map[string]interface{}{"currencyCode": "USD", "units": "9", "nanos": 990000000}},
+						},
+					}},
+				},
+				{"productId": "vip", "archived": false},
+			},
+		},
+		"GET /subscriptions/premium/basePlans/monthly/offers": map[string]interface{}{
+			"subscriptionOffers": []map[string]string{{"offerId": "intro", "state": "ACTIVE"}},
+		},
+	}}
+	c := newTestClient(t, publisher)
+
+	catalog, err := LoadCatalog([]byte(testCatalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := c.DiffCatalog(context.Background(), catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	want := []string{
+		"create product coins_500",
+		"deactivate product coins_old",
+		"activate base_plan premium/monthly",
+		"create offer premium/monthly/trial",
+		"activate offer premium/monthly/trial",
+		"deactivate offer premium/monthly/intro",
+		"deactivate subscription vip",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffCatalog() got = %v, want %v", got, want)
+	}
+
+	if err = c.ApplyCatalog(context.Background(), "com.example.other", changes); err == nil {
+		t.Errorf("ApplyCatalog() err = nil, want package mismatch")
+	}
+
+	publisher.requests = nil
+	if err = c.ApplyCatalog(context.Background(), catalog.PackageName, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	wantRequests := []string{
+		"POST /inappproducts",
+		"GET /inappproducts/coins_old",
+		"PUT /inappproducts/coins_old",
+		"POST /subscriptions/premium/basePlans/monthly:activate",
+		"POST /subscriptions/premium/basePlans/monthly/offers",
+		"POST /subscriptions/premium/basePlans/monthly/offers/trial:activate",
+		"POST /subscriptions/premium/basePlans/monthly/offers/intro:deactivate",
+		"POST /subscriptions/vip:archive",
+	}
+	if !reflect.DeepEqual(publisher.requests, wantRequests) {
+		t.Errorf("ApplyCatalog() requests = %v, want %v", publisher.requests, wantRequests)
+	}
+}
+
+func TestClient_diffSubscriptionPrune(t *testing.T) {
+	c := newTestClient(t, &testPublisher{})
+	want := &Subscription{ProductID: "premium", BasePlans: []BasePlan{{BasePlanID: "monthly"}}}
+	got := &Subscription{ProductID: "premium", BasePlans: []BasePlan{
+		{BasePlanID: "monthly", State: BasePlanStateActive},
+		{BasePlanID: "yearly", State: BasePlanStateActive},
+		{BasePlanID: "weekly", State: BasePlanStateInactive},
+	}}
+
+	for _, prune := range []bool{false, true} {
+		changes, err := c.diffSubscription(context.Background(), "com.example.app", want, got, prune)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var deactivated []string
+		for _, change := range changes {
+			if change.Action == CatalogActionDeactivate {
+				deactivated = append(deactivated, change.String())
+			}
+		}
+		var wantDeactivated []string
+		if prune {
+			wantDeactivated = []string{"deactivate base_plan premium/yearly"}
+		}
+		if !reflect.DeepEqual(deactivated, wantDeactivated) {
+			t.Errorf("diffSubscription(prune=%v) deactivated = %v, want %v", prune, deactivated, wantDeactivated)
+		}
+	}
+}
+
+func TestClient_diffSubscriptionArchived(t *testing.T) {
+	c := newTestClient(t, &testPublisher{})
+	want := &Subscription{ProductID: "vip", BasePlans: []BasePlan{{BasePlanID: "monthly"}}}
+	got := &Subscription{ProductID: "vip", Archived: true, BasePlans: []BasePlan{{BasePlanID: "monthly", State: BasePlanStateInactive}}}
+
+	changes, err := c.diffSubscription(context.Background(), "com.example.app", want, got, false)
+	if err == nil || !strings.Contains(err.Error(), "vip is archived") || changes != nil {
+		t.Errorf("diffSubscription() got = %v, err = %v, want archived error", changes, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -16,22 +17,70 @@ import (
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/androidpublisher/v3"
 	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
 )
 
 type Client struct {
 	credentials     []byte
 	googlePublisher *androidpublisher.Service
+	// publisherClient is the authorized client used for the endpoints
+	// not covered by googlePublisher, such as monetization subscriptions.
+	publisherClient *http.Client
 	client          *http.Client
+	accountIDSalt   string
+}
+
+type Config struct {
+	PackageName string `yaml:"package_name"`
+	Credentials string `yaml:"credentials"`
+	// CredentialsFile is read when Credentials is empty, Registry reloads it after the key is rotated.
+	CredentialsFile string `yaml:"credentials_file"`
+	// AccountIDSalt hashes the user id set as obfuscatedAccountId, keep it empty if the raw user id is used.
+	AccountIDSalt string `yaml:"account_id_salt"`
+}
+
+func (c Config) credentials() ([]byte, error) {
+	if c.Credentials != "" || c.CredentialsFile == "" {
+		return []byte(c.Credentials), nil
+	}
+
+	return ioutil.ReadFile(c.CredentialsFile)
 }
 
 func NewClient(credentialsJSON []byte) (*Client, error) {
-	service, err := androidpublisher.NewService(context.Background(), option.WithCredentialsJSON(credentialsJSON))
+	return newClient(context.Background(), credentialsJSON, option.WithCredentialsJSON(credentialsJSON))
+}
+
+func NewClientWithConfig(config Config) (*Client, error) {
+	credentials, err := config.credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewClient(credentials)
+	if err != nil {
+		return nil, err
+	}
+	c.accountIDSalt = config.AccountIDSalt
+
+	return c, nil
+}
+
+func newClient(ctx context.Context, credentialsJSON []byte, opts ...option.ClientOption) (*Client, error) {
+	service, err := androidpublisher.NewService(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
+
+	publisherClient, _, err := htransport.NewClient(ctx, append([]option.ClientOption{option.WithScopes(androidpublisher.AndroidpublisherScope)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Client{
 		credentials:     credentialsJSON,
 		googlePublisher: service,
+		publisherClient: publisherClient,
 		client:          httpx.NewClient(),
 	}, nil
 }
@@ -45,16 +94,49 @@ type API interface {
 	GetSubscription(ctx context.Context, packageName, subscriptionID, purchaseToken string) (*androidpublisher.SubscriptionPurchase, error)
 	GetSubscriptionByReceipt(ctx context.Context, receipt Receipt) (*androidpublisher.SubscriptionPurchase, error)
 
+	// purchases bound to an account, accountID is checked against obfuscatedExternalAccountId and obfuscatedExternalProfileId
+	// https://developer.android.com/google/play/billing/security#verify
+	ObfuscateAccountID(accountID string) string
+	GetPurchaseByReceiptForAccount(ctx context.Context, receipt Receipt, accountID string) (*androidpublisher.ProductPurchase, error)
+	GetSubscriptionByReceiptForAccount(ctx context.Context, receipt Receipt, accountID string) (*androidpublisher.SubscriptionPurchase, error)
+
 	Acknowledge(ctx context.Context, packageName string, productID string, token string) error
 	AcknowledgeByReceipt(ctx context.Context, receipt Receipt) error
 
 	GetVoidedPurchase(ctx context.Context, v VoidedPurchase) (voidedPurchases []*androidpublisher.VoidedPurchase, nextPageToken string, err error)
 	DealVoidPurchase(ctx context.Context, d DealVoidedPurchase) (dealFailed []*androidpublisher.VoidedPurchase, err error)
 
+	RefundOrder(ctx context.Context, packageName string, orderID string, revoke bool, dealFunc VoidedPurchaseHandle) error
+	RevokeSubscription(ctx context.Context, packageName, subscriptionID, token string, dealFunc VoidedPurchaseHandle) error
+	RefundOrderByReceipt(ctx context.Context, receipt Receipt, revoke bool, dealFunc VoidedPurchaseHandle) error
+
 	// ReceiveRealTimeDeveloperNotification ...
 	// RTDN means RealTimeDeveloperNotification
 	// https://developer.android.google.cn/google/play/billing/rtdn-reference?hl=zh-cn
 	ReceiveRealTimeDeveloperNotification(ctx context.Context, req *http.Request) error
+
+	// catalog management
+	// https://developers.google.com/android-publisher/api-ref/rest/v3/inappproducts
+	// https://developers.google.com/android-publisher/api-ref/rest/v3/monetization.subscriptions
+	ListInAppProducts(ctx context.Context, packageName string) ([]*androidpublisher.InAppProduct, error)
+	CreateInAppProduct(ctx context.Context, packageName string, product *androidpublisher.InAppProduct) (*androidpublisher.InAppProduct, error)
+	UpdateInAppProduct(ctx context.Context, packageName string, product *androidpublisher.InAppProduct) (*androidpublisher.InAppProduct, error)
+	DeactivateInAppProduct(ctx context.Context, packageName string, sku string) error
+
+	ListSubscriptions(ctx context.Context, packageName string) ([]*Subscription, error)
+	CreateSubscription(ctx context.Context, packageName string, subscription *Subscription) (*Subscription, error)
+	UpdateSubscription(ctx context.Context, packageName string, subscription *Subscription) (*Subscription, error)
+	ArchiveSubscription(ctx context.Context, packageName string, productID string) error
+	ActivateBasePlan(ctx context.Context, packageName, productID, basePlanID string) error
+	DeactivateBasePlan(ctx context.Context, packageName, productID, basePlanID string) error
+	ListOffers(ctx context.Context, packageName, productID, basePlanID string) ([]*SubscriptionOffer, error)
+	CreateOffer(ctx context.Context, packageName, productID, basePlanID string, offer *SubscriptionOffer) (*SubscriptionOffer, error)
+	UpdateOffer(ctx context.Context, packageName, productID, basePlanID string, offer *SubscriptionOffer) (*SubscriptionOffer, error)
+	ActivateOffer(ctx context.Context, packageName, productID, basePlanID, offerID string) error
+	DeactivateOffer(ctx context.Context, packageName, productID, basePlanID, offerID string) error
+
+	DiffCatalog(ctx context.Context, catalog *Catalog) ([]CatalogChange, error)
+	ApplyCatalog(ctx context.Context, packageName string, changes []CatalogChange) error
 }
 
 var _ API = (*Client)(nil)
@@ -63,7 +145,7 @@ func (c *Client) GetToken(ctx context.Context) (tokenInfo *oauth2.Token, err err
 	conf, err := google.JWTConfigFromJSON(c.credentials, androidpublisher.AndroidpublisherScope)
 	if err != nil {
 		log.L(ctx).Warn("google get jwt config failed", zap.Error(err))
-		return nil, err
+		return nil, &APIError{Kind: ErrInvalidCredentials, Err: err}
 	}
 
 	authCtx := context.WithValue(context.Background(), oauth2.HTTPClient, c.client)
@@ -71,7 +153,7 @@ func (c *Client) GetToken(ctx context.Context) (tokenInfo *oauth2.Token, err err
 	token, err := authTransport.Source.Token()
 	if err != nil {
 		log.L(ctx).Warn("auth transport get token failed", zap.Error(err))
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	return token, nil
@@ -81,10 +163,10 @@ func (c *Client) GetPurchase(ctx context.Context, packageName string, productID
 	res, err := c.googlePublisher.Purchases.Products.Get(packageName, productID, purchaseToken).Do()
 	if err != nil {
 		log.L(ctx).Warn("google play get purchase failed", zap.Error(err))
-		return nil, err
+		return nil, classifyPurchaseError(err)
 	}
 
-	if res.PurchaseState != PurchaseStatePurchased {
+	if err = PurchaseState(res.PurchaseState).Err(); err != nil {
 		log.L(ctx).Warn("google play purchase status is not purchase", zap.Int64("purchase_status", res.PurchaseState))
 		return res, err
 	}
@@ -96,7 +178,7 @@ func (c *Client) GetSubscription(ctx context.Context, packageName, subscriptionI
 	res, err := c.googlePublisher.Purchases.Subscriptions.Get(packageName, subscriptionID, purchaseToken).Do()
 	if err != nil {
 		log.L(ctx).Warn("google play get subscription failed", zap.Error(err))
-		return nil, err
+		return nil, classifyPurchaseError(err)
 	}
 
 	return res, nil
@@ -107,7 +189,7 @@ func (c *Client) Acknowledge(ctx context.Context, packageName string, productID
 	err := c.googlePublisher.Purchases.Products.Acknowledge(packageName, productID, token, nil).Do()
 	if err != nil {
 		log.L(ctx).Warn("google play get subscription failed", zap.Error(err))
-		return err
+		return classifyPurchaseError(err)
 	}
 
 	return nil
@@ -126,44 +208,58 @@ func (c *Client) AcknowledgeByReceipt(ctx context.Context, receipt Receipt) erro
 }
 
 func (c *Client) ReceiveRealTimeDeveloperNotification(ctx context.Context, req *http.Request) error {
+	rtdnData, err := readRTDN(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return c.dealRTDN(ctx, rtdnData)
+}
+
+func readRTDN(ctx context.Context, req *http.Request) (*RTDNData, error) {
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
 		log.L(ctx).Warn("read rtdn body failed", zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	var rtdnBody RTDNBody
 	err = json.Unmarshal(body, &rtdnBody)
 	if err != nil {
 		log.L(ctx).Warn("read rtdn json unmarshal failed", zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	data, err := base64.StdEncoding.DecodeString(rtdnBody.Message.Data)
 	if err != nil {
 		log.L(ctx).Warn("read rtdn decode failed", zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	var rtdnData RTDNData
 	err = json.Unmarshal(data, &rtdnData)
 	if err != nil {
 		log.L(ctx).Warn("read rtdn json unmarshal after decode failed", zap.Error(err))
-		return err
+		return nil, err
 	}
 
+	return &rtdnData, nil
+}
+
+func (c *Client) dealRTDN(ctx context.Context, rtdnData *RTDNData) error {
 	if rtdnData.OneTimeProductNotification == nil {
-		log.L(ctx).Info("rtdn is not one time product notification", zap.Error(err))
+		log.L(ctx).Info("rtdn is not one time product notification")
 		return nil
 	}
 
 	if rtdnData.OneTimeProductNotification.NotificationType != ONE_TIME_PRODUCT_PURCHASED {
-		log.L(ctx).Info("rtdn is not one time product purchases notification", zap.Error(err))
+		log.L(ctx).Info("rtdn is not one time product purchases notification")
 		return nil
 	}
 
 	res, err := c.GetPurchase(ctx, rtdnData.PackageName, rtdnData.OneTimeProductNotification.Sku, rtdnData.OneTimeProductNotification.PurchaseToken)
-	if res != nil {
+	if errors.Is(err, ErrPurchasePending) || errors.Is(err, ErrPurchaseCanceled) {
+		log.L(ctx).Info("rtdn purchase is not purchased, skip acknowledge", zap.Error(err), zap.Any("rtdn", rtdnData))
 		return nil
 	}
 	if err != nil {
@@ -171,6 +267,10 @@ func (c *Client) ReceiveRealTimeDeveloperNotification(ctx context.Context, req *
 		return err
 	}
 
+	if AcknowledgementState(res.AcknowledgementState) == AcknowledgementStateAcknowledged {
+		return nil
+	}
+
 	err = c.Acknowledge(ctx, rtdnData.PackageName, rtdnData.OneTimeProductNotification.Sku, rtdnData.OneTimeProductNotification.PurchaseToken)
 	if err != nil {
 		log.L(ctx).Info("rtdn acknowledge  failed", zap.Error(err), zap.Any("rtdn", rtdnData))
@@ -190,7 +290,7 @@ func (c *Client) GetVoidedPurchase(ctx context.Context, v VoidedPurchase) (voide
 	if err != nil {
 		fmt.Println(res)
 		log.L(ctx).Warn("get voided purchase failed", zap.Error(err), zap.Any("req", v))
-		return nil, "", err
+		return nil, "", classifyError(err)
 	}
 	if res.TokenPagination != nil {
 		nextPageToken = res.TokenPagination.NextPageToken
@@ -206,7 +306,7 @@ func (c *Client) DealVoidPurchase(ctx context.Context, d DealVoidedPurchase) (de
 	res, err := c.googlePublisher.Purchases.Voidedpurchases.List(d.PackageName).StartTime(d.StartTime.Unix()).EndTime(d.EndTime.Unix()).Do()
 	if err != nil {
 		log.L(ctx).Warn("list voided purchase failed", zap.Error(err), zap.Any("req", d))
-		return nil, err
+		return nil, classifyError(err)
 	}
 	if res.TokenPagination != nil {
 		nextPageToken = res.TokenPagination.NextPageToken
@@ -230,7 +330,7 @@ func (c *Client) DealVoidPurchase(ctx context.Context, d DealVoidedPurchase) (de
 		res, err := c.googlePublisher.Purchases.Voidedpurchases.List(d.PackageName).StartTime(d.StartTime.Unix()).EndTime(d.EndTime.Unix()).Token(nextPageToken).Do()
 		if err != nil {
 			log.L(ctx).Warn("list voided purchase failed", zap.Error(err), zap.Any("req", d))
-			return dealFailed, err
+			return dealFailed, classifyError(err)
 		}
 		if res.TokenPagination != nil {
 			nextPageToken = res.TokenPagination.NextPageToken
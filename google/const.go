@@ -2,15 +2,46 @@ package google
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"google.golang.org/api/androidpublisher/v3"
 )
 
+// PurchaseState https://developers.google.com/android-publisher/api-ref/rest/v3/purchases.products
+type PurchaseState int64
+
+const (
+	PurchaseStatePurchased PurchaseState = 0
+	PurchaseStateCanceled  PurchaseState = 1
+	PurchaseStatePending   PurchaseState = 2
+)
+
+// Err returns nil for purchased purchases, ErrPurchaseCanceled or ErrPurchasePending otherwise.
+func (s PurchaseState) Err() error {
+	switch s {
+	case PurchaseStatePurchased:
+		return nil
+	case PurchaseStateCanceled:
+		return ErrPurchaseCanceled
+	case PurchaseStatePending:
+		return ErrPurchasePending
+	}
+	return fmt.Errorf("unknown purchase state %d", s)
+}
+
+type AcknowledgementState int64
+
+const (
+	AcknowledgementStatePending      AcknowledgementState = 0
+	AcknowledgementStateAcknowledged AcknowledgementState = 1
+)
+
+type ConsumptionState int64
+
 const (
-	PurchaseStatePurchased = 0
-	PurchaseStateCanceled  = 1
-	PurchaseStatePending   = 2
+	ConsumptionStatePending  ConsumptionState = 0
+	ConsumptionStateConsumed ConsumptionState = 1
 )
 
 type NotificationType int
@@ -90,9 +121,29 @@ type VoidedPurchase struct {
 	Token       string
 }
 
+// VoidedPurchaseHandle deals a voided purchase, it is called for voided purchases listed
+// from google play and for the orders refunded by RefundOrder.
+type VoidedPurchaseHandle = func(ctx context.Context, voidedPurchase *androidpublisher.VoidedPurchase) error
+
 type DealVoidedPurchase struct {
 	PackageName string
 	StartTime   time.Time
 	EndTime     time.Time
-	DealFunc    func(ctx context.Context, voidedPurchase *androidpublisher.VoidedPurchase) error
-}
\ No newline at end of file
+	DealFunc    VoidedPurchaseHandle
+}
+
+// https://developers.google.com/android-publisher/api-ref/rest/v3/purchases.voidedpurchases
+const (
+	VoidedSourceUser      = 0
+	VoidedSourceDeveloper = 1
+	VoidedSourceGoogle    = 2
+
+	VoidedReasonOther              = 0
+	VoidedReasonRemorse            = 1
+	VoidedReasonNotReceived        = 2
+	VoidedReasonDefective          = 3
+	VoidedReasonAccidentalPurchase = 4
+	VoidedReasonFraud              = 5
+	VoidedReasonFriendlyFraud      = 6
+	VoidedReasonChargeback         = 7
+)
\ No newline at end of file
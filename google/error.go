@@ -0,0 +1,161 @@
+package google
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+var (
+	// ErrAccountMismatch returns when the obfuscated account id of a purchase
+	// is not the one of the expected user, the receipt may be replayed by another account.
+	ErrAccountMismatch = errors.New("purchase belongs to another account")
+	// ErrAccountNotBound returns when a purchase carries no obfuscated account id or profile id.
+	ErrAccountNotBound = errors.New("purchase is not bound to an account")
+
+	// ErrPurchasePending returns when the purchase is not paid yet.
+	ErrPurchasePending = errors.New("purchase is pending")
+	// ErrPurchaseCanceled returns when the purchase is canceled.
+	ErrPurchaseCanceled = errors.New("purchase is canceled")
+	// ErrTokenNotFound returns when the purchase token does not exist or is no longer valid.
+	ErrTokenNotFound = errors.New("purchase token not found")
+	// ErrNotFound returns when a resource other than a purchase, such as a sku or an order, does not exist.
+	ErrNotFound = errors.New("google play resource not found")
+	// ErrQuotaExceeded returns when the google play developer api quota is used up, retry later.
+	ErrQuotaExceeded = errors.New("google play api quota exceeded")
+	// ErrInvalidCredentials returns when the service account is invalid or has no permission on the app.
+	ErrInvalidCredentials = errors.New("invalid google play credentials")
+
+	// ErrUnknownPackage returns when the package is not configured in the Registry.
+	ErrUnknownPackage = errors.New("unknown package name")
+)
+
+// APIError is a googleapi error classified by Kind,
+// errors.Is matches Kind and errors.As still finds the *googleapi.Error.
+type APIError struct {
+	Kind error
+	Err  error
+}
+
+func (e *APIError) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == e.Kind
+}
+
+var (
+	tokenNotFoundReasons      = map[string]bool{"purchaseTokenDoesNotMatchPackageName": true, "purchaseTokenNoLongerValid": true}
+	notFoundReasons           = map[string]bool{"notFound": true}
+	quotaExceededReasons      = map[string]bool{"rateLimitExceeded": true, "userRateLimitExceeded": true, "quotaExceeded": true, "dailyLimitExceeded": true}
+	invalidCredentialsReasons = map[string]bool{"authError": true, "forbidden": true, "permissionDenied": true, "insufficientPermissions": true}
+)
+
+// classifyError maps googleapi and oauth2 errors to the sentinel errors, other errors are returned as is.
+// A missing resource is ErrNotFound, use classifyPurchaseError for the purchases endpoints.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return classifyRetrieveError(err, retrieveErr)
+	}
+
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	var kind error
+	switch {
+	case hasReason(apiErr, tokenNotFoundReasons):
+		kind = ErrTokenNotFound
+	case apiErr.Code == http.StatusNotFound || apiErr.Code == http.StatusGone || hasReason(apiErr, notFoundReasons):
+		kind = ErrNotFound
+	case apiErr.Code == http.StatusTooManyRequests || hasReason(apiErr, quotaExceededReasons):
+		kind = ErrQuotaExceeded
+	case apiErr.Code == http.StatusUnauthorized || apiErr.Code == http.StatusForbidden || hasReason(apiErr, invalidCredentialsReasons):
+		kind = ErrInvalidCredentials
+	default:
+		return err
+	}
+
+	return &APIError{Kind: kind, Err: err}
+}
+
+// classifyPurchaseError classifies the errors of the purchases endpoints, where the missing resource is the purchase token.
+func classifyPurchaseError(err error) error {
+	err = classifyError(err)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Kind == ErrNotFound {
+		apiErr.Kind = ErrTokenNotFound
+	}
+
+	return err
+}
+
+// classifyRetrieveError classifies the errors of the token endpoint by the status code,
+// only the rejected requests are ErrInvalidCredentials, server errors are left retryable.
+func classifyRetrieveError(err error, retrieveErr *oauth2.RetrieveError) error {
+	if retrieveErr.Response == nil {
+		return err
+	}
+
+	switch code := retrieveErr.Response.StatusCode; {
+	case code == http.StatusTooManyRequests:
+		return &APIError{Kind: ErrQuotaExceeded, Err: err}
+	case code == http.StatusBadRequest || code == http.StatusUnauthorized:
+		return &APIError{Kind: ErrInvalidCredentials, Err: err}
+	default:
+		return err
+	}
+}
+
+func hasReason(err *googleapi.Error, reasons map[string]bool) bool {
+	for _, item := range err.Errors {
+		if reasons[item.Reason] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRetryable reports whether the request may succeed if retried later.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrQuotaExceeded) {
+		return true
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code >= http.StatusInternalServerError
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return retrieveErr.Response != nil && retrieveErr.Response.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+
+	return false
+}
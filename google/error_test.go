@@ -0,0 +1,119 @@
+package google
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		purchase      bool
+		wantKind      error
+		wantRetryable bool
+	}{
+		{
+			name:     "token not found",
+			err:      &googleapi.Error{Code: http.StatusNotFound},
+			purchase: true,
+			wantKind: ErrTokenNotFound,
+		},
+		{
+			name:     "sku not found",
+			err:      &googleapi.Error{Code: http.StatusNotFound, Errors: []googleapi.ErrorItem{{Reason: "notFound"}}},
+			wantKind: ErrNotFound,
+		},
+		{
+			name:     "token no longer valid",
+			err:      &googleapi.Error{Code: http.StatusBadRequest, Errors: []googleapi.ErrorItem{{Reason: "purchaseTokenNoLongerValid"}}},
+			wantKind: ErrTokenNotFound,
+		},
+		{
+			name:          "quota exceeded",
+			err:           &googleapi.Error{Code: http.StatusTooManyRequests},
+			wantKind:      ErrQuotaExceeded,
+			wantRetryable: true,
+		},
+		{
+			name:     "no permission",
+			err:      &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "permissionDenied"}}},
+			wantKind: ErrInvalidCredentials,
+		},
+		{
+			name:          "server error",
+			err:           &googleapi.Error{Code: http.StatusServiceUnavailable},
+			wantRetryable: true,
+		},
+		{
+			name:     "invalid service account",
+			err:      &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusBadRequest}, Body: []byte(`{"error":"invalid_grant"}`)},
+			wantKind: ErrInvalidCredentials,
+		},
+		{
+			name:          "token endpoint rate limited",
+			err:           &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusTooManyRequests}},
+			wantKind:      ErrQuotaExceeded,
+			wantRetryable: true,
+		},
+		{
+			name:          "token endpoint unavailable",
+			err:           &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}},
+			wantRetryable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classify := classifyError
+			if tt.purchase {
+				classify = classifyPurchaseError
+			}
+			err := classify(tt.err)
+			if tt.wantKind != nil && !errors.Is(err, tt.wantKind) {
+				t.Errorf("classifyError() = %v, want %v", err, tt.wantKind)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("classifyError() = %v, original error lost", err)
+			}
+			if tt.wantKind == nil && errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("classifyError() = %v, want not %v", err, ErrInvalidCredentials)
+			}
+			if got := IsRetryable(err); got != tt.wantRetryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+func TestClient_GetPurchase(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   PurchaseState
+		wantErr error
+	}{
+		{name: "purchased", state: PurchaseStatePurchased},
+		{name: "canceled", state: PurchaseStateCanceled, wantErr: ErrPurchaseCanceled},
+		{name: "pending", state: PurchaseStatePending, wantErr: ErrPurchasePending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &testPublisher{routes: map[string]interface{}{
+				"GET /purchases/products/coins_100/tokens/token": map[string]interface{}{"purchaseState": tt.state},
+			}}
+			c := newTestClient(t, publisher)
+
+			res, err := c.GetPurchase(context.Background(), "com.example.app", "coins_100", "token")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetPurchase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if res == nil {
+				t.Error("GetPurchase() purchase is nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/linhoi/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+const voidedPurchaseKind = "androidpublisher#voidedPurchase"
+
+// RefundOrder refunds a one-time purchase or subscription order, revoke removes the entitlement as well.
+// If revoke is true the order is passed to dealFunc as a voided purchase, so it can be handled the same way
+// as DealVoidPurchase does; a refund without revoke keeps the entitlement and dealFunc is not called.
+// https://developers.google.com/android-publisher/api-ref/rest/v3/orders/refund
+func (c *Client) RefundOrder(ctx context.Context, packageName string, orderID string, revoke bool, dealFunc VoidedPurchaseHandle) error {
+	err := c.googlePublisher.Orders.Refund(packageName, orderID).Revoke(revoke).Context(ctx).Do()
+	if err != nil {
+		log.L(ctx).Warn("google play refund order failed", zap.Error(err), zap.String("order_id", orderID), zap.Bool("revoke", revoke))
+		return classifyError(err)
+	}
+	if !revoke {
+		return nil
+	}
+
+	return dealRefunded(ctx, &androidpublisher.VoidedPurchase{
+		Kind:             voidedPurchaseKind,
+		OrderId:          orderID,
+		VoidedSource:     VoidedSourceDeveloper,
+		VoidedReason:     VoidedReasonOther,
+		VoidedTimeMillis: time.Now().UnixNano() / int64(time.Millisecond),
+	}, dealFunc)
+}
+
+// RevokeSubscription refunds and immediately revokes a subscription, the subscription stops recurring.
+// https://developers.google.com/android-publisher/api-ref/rest/v3/purchases.subscriptions/revoke
+func (c *Client) RevokeSubscription(ctx context.Context, packageName, subscriptionID, token string, dealFunc VoidedPurchaseHandle) error {
+	subscription, err := c.GetSubscription(ctx, packageName, subscriptionID, token)
+	if err != nil {
+		return err
+	}
+
+	err = c.googlePublisher.Purchases.Subscriptions.Revoke(packageName, subscriptionID, token).Context(ctx).Do()
+	if err != nil {
+		log.L(ctx).Warn("google play revoke subscription failed", zap.Error(err), zap.String("subscription_id", subscriptionID))
+		return classifyPurchaseError(err)
+	}
+
+	return dealRefunded(ctx, &androidpublisher.VoidedPurchase{
+		Kind:               voidedPurchaseKind,
+		OrderId:            subscription.OrderId,
+		PurchaseToken:      token,
+		PurchaseTimeMillis: subscription.StartTimeMillis,
+		VoidedSource:       VoidedSourceDeveloper,
+		VoidedReason:       VoidedReasonOther,
+		VoidedTimeMillis:   time.Now().UnixNano() / int64(time.Millisecond),
+	}, dealFunc)
+}
+
+// RefundOrderByReceipt revokes subscriptions by purchases.subscriptions.revoke and refunds others by orders.refund,
+// dealFunc is only called if revoke is true, like RefundOrder.
+func (c *Client) RefundOrderByReceipt(ctx context.Context, receipt Receipt, revoke bool, dealFunc VoidedPurchaseHandle) error {
+	if revoke && receipt.SubscriptionID != "" {
+		return c.RevokeSubscription(ctx, receipt.PackageName, receipt.SubscriptionID, receipt.Token, dealFunc)
+	}
+
+	return c.RefundOrder(ctx, receipt.PackageName, receipt.OrderID, revoke, func(ctx context.Context, voidedPurchase *androidpublisher.VoidedPurchase) error {
+		voidedPurchase.PurchaseToken = receipt.Token
+		voidedPurchase.PurchaseTimeMillis = int64(receipt.Time)
+		if dealFunc == nil {
+			return nil
+		}
+		return dealFunc(ctx, voidedPurchase)
+	})
+}
+
+func dealRefunded(ctx context.Context, voidedPurchase *androidpublisher.VoidedPurchase, dealFunc VoidedPurchaseHandle) error {
+	if dealFunc == nil {
+		return nil
+	}
+
+	err := dealFunc(ctx, voidedPurchase)
+	if err != nil {
+		log.L(ctx).Error("deal refunded order failed", zap.Error(err), zap.Any("voided_purchase", voidedPurchase))
+		return fmt.Errorf("order %s refunded but deal failed: %w", voidedPurchase.OrderId, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package google
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/androidpublisher/v3"
+)
+
+func TestClient_RefundOrderByReceipt(t *testing.T) {
+	tests := []struct {
+		name         string
+		receipt      Receipt
+		revoke       bool
+		wantRequests []string
+		wantVoided   *androidpublisher.VoidedPurchase
+	}{
+		{
+			name:         "refund product",
+			receipt:      Receipt{ReceiptCore: ReceiptCore{PackageName: "com.example.app", ProductID: "coins_100", Token: "token-1"}, OrderID: "GPA.1", Time: 1000},
+			wantRequests: []string{"POST /orders/GPA.1:refund"},
+		},
+		{
+			name:         "refund and revoke product",
+			receipt:      Receipt{ReceiptCore: ReceiptCore{PackageName: "com.example.app", ProductID: "coins_100", Token: "token-1"}, OrderID: "GPA.1", Time: 1000},
+			revoke:       true,
+			wantRequests: []string{"POST /orders/GPA.1:refund"},
+			wantVoided:   &androidpublisher.VoidedPurchase{OrderId: "GPA.1", PurchaseToken: "token-1", PurchaseTimeMillis: 1000},
+		},
+		{
+			name:         "revoke subscription",
+			receipt:      Receipt{ReceiptCore: ReceiptCore{PackageName: "com.example.app", SubscriptionID: "premium", Token: "token-2"}, OrderID: "GPA.2..0"},
+			revoke:       true,
+			wantRequests: []string{"GET /purchases/subscriptions/premium/tokens/token-2", "POST /purchases/subscriptions/premium/tokens/token-2:revoke"},
+			wantVoided:   &androidpublisher.VoidedPurchase{OrderId: "GPA.2..1", PurchaseToken: "token-2", PurchaseTimeMillis: 2000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &testPublisher{routes: map[string]interface{}{
+				"GET /purchases/subscriptions/premium/tokens/token-2": map[string]string{"orderId": "GPA.2..1", "startTimeMillis": "2000"},
+			}}
+			c := newTestClient(t, publisher)
+
+			var got *androidpublisher.VoidedPurchase
+			err := c.RefundOrderByReceipt(context.Background(), tt.receipt, tt.revoke, func(ctx context.Context, voidedPurchase *androidpublisher.VoidedPurchase) error {
+				got = voidedPurchase
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("RefundOrderByReceipt() error = %v", err)
+			}
+			if !reflect.DeepEqual(publisher.requests, tt.wantRequests) {
+				t.Errorf("RefundOrderByReceipt() requests = %v, want %v", publisher.requests, tt.wantRequests)
+			}
+			if tt.wantVoided == nil {
+				if got != nil {
+					t.Errorf("RefundOrderByReceipt() voided purchase = %+v, want deal func not called", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("RefundOrderByReceipt() deal func not called")
+			}
+			if got.OrderId != tt.wantVoided.OrderId || got.PurchaseToken != tt.wantVoided.PurchaseToken ||
+				got.PurchaseTimeMillis != tt.wantVoided.PurchaseTimeMillis || got.VoidedSource != VoidedSourceDeveloper {
+				t.Errorf("RefundOrderByReceipt() voided purchase = %+v, want %+v", got, tt.wantVoided)
+			}
+		})
+	}
+}
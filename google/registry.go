@@ -0,0 +1,193 @@
+package google
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/linhoi/kit/log"
+	"go.uber.org/zap"
+	"google.golang.org/api/androidpublisher/v3"
+	"gopkg.in/yaml.v2"
+)
+
+// RegistryConfig configures the apps served by a Registry, apps may belong to different developer accounts.
+type RegistryConfig struct {
+	Apps []Config `yaml:"apps"`
+}
+
+// Registry selects the Client of an app by package name.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[string]*registryEntry
+	// reloadMu serializes Reload, so a reload never builds on the clients another one is replacing.
+	reloadMu sync.Mutex
+
+	newClient func(config Config) (*Client, error)
+}
+
+type registryEntry struct {
+	client        *Client
+	credentials   []byte
+	accountIDSalt string
+}
+
+// NewRegistry ...
+func NewRegistry(config RegistryConfig) (*Registry, error) {
+	r := &Registry{
+		clients:   make(map[string]*registryEntry, len(config.Apps)),
+		newClient: NewClientWithConfig,
+	}
+
+	if err := r.Reload(config); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// LoadRegistry creates a Registry from yaml.
+func LoadRegistry(data []byte) (*Registry, error) {
+	var config RegistryConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	return NewRegistry(config)
+}
+
+// Reload replaces the apps of the registry, clients are only rebuilt when their credentials or salt changed,
+// so rotated service account keys in credentials_file are picked up by calling Reload again.
+// The registry is left untouched if any app fails to load.
+func (r *Registry) Reload(config RegistryConfig) error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	r.mu.RLock()
+	current := r.clients
+	r.mu.RUnlock()
+
+	clients := make(map[string]*registryEntry, len(config.Apps))
+	for _, app := range config.Apps {
+		if app.PackageName == "" {
+			return fmt.Errorf("google registry app package_name is empty")
+		}
+		if _, exist := clients[app.PackageName]; exist {
+			return fmt.Errorf("google registry app %s is duplicated", app.PackageName)
+		}
+
+		credentials, err := app.credentials()
+		if err != nil {
+			return fmt.Errorf("google registry app %s: %w", app.PackageName, err)
+		}
+
+		if entry, ok := current[app.PackageName]; ok && bytes.Equal(entry.credentials, credentials) && entry.accountIDSalt == app.AccountIDSalt {
+			clients[app.PackageName] = entry
+			continue
+		}
+
+		app.Credentials = string(credentials)
+		client, err := r.newClient(app)
+		if err != nil {
+			return fmt.Errorf("google registry app %s: %w", app.PackageName, err)
+		}
+		clients[app.PackageName] = &registryEntry{client: client, credentials: credentials, accountIDSalt: app.AccountIDSalt}
+	}
+
+	r.mu.Lock()
+	r.clients = clients
+	r.mu.Unlock()
+
+	return nil
+}
+
+// ReloadFile reloads the registry from a yaml file.
+func (r *Registry) ReloadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var config RegistryConfig
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return err
+	}
+
+	return r.Reload(config)
+}
+
+// WatchFile reloads the registry from path every interval until ctx is done.
+func (r *Registry) WatchFile(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.ReloadFile(path); err != nil {
+				log.L(ctx).Warn("google registry reload failed", zap.Error(err), zap.String("path", path))
+			}
+		}
+	}
+}
+
+// Client returns the client of the package.
+func (r *Registry) Client(packageName string) (*Client, error) {
+	r.mu.RLock()
+	entry, ok := r.clients[packageName]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPackage, packageName)
+	}
+
+	return entry.client, nil
+}
+
+func (r *Registry) GetPurchaseByReceipt(ctx context.Context, receipt Receipt) (*androidpublisher.ProductPurchase, error) {
+	c, err := r.Client(receipt.PackageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetPurchaseByReceipt(ctx, receipt)
+}
+
+func (r *Registry) GetSubscriptionByReceipt(ctx context.Context, receipt Receipt) (*androidpublisher.SubscriptionPurchase, error) {
+	c, err := r.Client(receipt.PackageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetSubscriptionByReceipt(ctx, receipt)
+}
+
+func (r *Registry) AcknowledgeByReceipt(ctx context.Context, receipt Receipt) error {
+	c, err := r.Client(receipt.PackageName)
+	if err != nil {
+		return err
+	}
+
+	return c.AcknowledgeByReceipt(ctx, receipt)
+}
+
+// ReceiveRealTimeDeveloperNotification deals the notification with the client of its package.
+func (r *Registry) ReceiveRealTimeDeveloperNotification(ctx context.Context, req *http.Request) error {
+	rtdnData, err := readRTDN(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	c, err := r.Client(rtdnData.PackageName)
+	if err != nil {
+		log.L(ctx).Warn("rtdn package is not registered", zap.Error(err))
+		return err
+	}
+
+	return c.dealRTDN(ctx, rtdnData)
+}
@@ -0,0 +1,142 @@
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+)
+
+func newTestRegistry(t *testing.T, handler http.Handler, config RegistryConfig) (*Registry, *[]string) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	var loaded []string
+	r := &Registry{
+		clients: map[string]*registryEntry{},
+		newClient: func(config Config) (*Client, error) {
+			loaded = append(loaded, config.PackageName+":"+config.Credentials)
+			c, err := newClient(context.Background(), []byte(config.Credentials), option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL+"/"))
+			if err != nil {
+				return nil, err
+			}
+			c.accountIDSalt = config.AccountIDSalt
+			return c, nil
+		},
+	}
+	if err := r.Reload(config); err != nil {
+		t.Fatal(err)
+	}
+
+	return r, &loaded
+}
+
+func TestRegistry_Reload(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.json")
+	if err := ioutil.WriteFile(keyFile, []byte(`{"key":"v1"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := RegistryConfig{Apps: []Config{
+		{PackageName: "com.example.a", Credentials: `{"key":"a"}`},
+		{PackageName: "com.example.b", CredentialsFile: keyFile},
+	}}
+	r, loaded := newTestRegistry(t, &testPublisher{}, config)
+
+	// nothing changed
+	if err := r.Reload(config); err != nil {
+		t.Fatal(err)
+	}
+
+	// key of b rotated
+	if err := ioutil.WriteFile(keyFile, []byte(`{"key":"v2"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(config); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{`com.example.a:{"key":"a"}`, `com.example.b:{"key":"v1"}`, `com.example.b:{"key":"v2"}`}
+	if !reflect.DeepEqual(*loaded, want) {
+		t.Errorf("Reload() loaded = %v, want %v", *loaded, want)
+	}
+
+	if _, err := r.Client("com.example.c"); !errors.Is(err, ErrUnknownPackage) {
+		t.Errorf("Client() error = %v, want %v", err, ErrUnknownPackage)
+	}
+
+	if err := r.Reload(RegistryConfig{Apps: []Config{{PackageName: "com.example.a", CredentialsFile: filepath.Join(dir, "missing.json")}}}); err == nil {
+		t.Error("Reload() with missing credentials file should fail")
+	}
+	if _, err := r.Client("com.example.b"); err != nil {
+		t.Errorf("Client() error = %v, registry should be untouched after a failed reload", err)
+	}
+}
+
+func TestRegistry_ReceiveRealTimeDeveloperNotification(t *testing.T) {
+	publisher := &testPublisher{routes: map[string]interface{}{
+		"GET /purchases/products/coins_100/tokens/token": map[string]interface{}{"purchaseState": PurchaseStatePurchased},
+	}}
+	r, _ := newTestRegistry(t, publisher, RegistryConfig{Apps: []Config{{PackageName: "com.example.app"}}})
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"packageName": "com.example.app",
+		"oneTimeProductNotification": map[string]interface{}{
+			"notificationType": ONE_TIME_PRODUCT_PURCHASED,
+			"purchaseToken":    "token",
+			"sku":              "coins_100",
+		},
+	})
+	var body RTDNBody
+	body.Message.Data = base64.StdEncoding.EncodeToString(data)
+	b, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/rtdn", bytes.NewReader(b))
+	if err := r.ReceiveRealTimeDeveloperNotification(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"GET /purchases/products/coins_100/tokens/token", "POST /purchases/products/coins_100/tokens/token:acknowledge"}
+	if !reflect.DeepEqual(publisher.requests, want) {
+		t.Errorf("ReceiveRealTimeDeveloperNotification() requests = %v, want %v", publisher.requests, want)
+	}
+}
+
+func TestRegistry_ReloadConcurrently(t *testing.T) {
+	config := RegistryConfig{Apps: []Config{{PackageName: "com.example.a", Credentials: `{"key":"a"}`}}}
+	r, loaded := newTestRegistry(t, &testPublisher{}, RegistryConfig{})
+	newClient := r.newClient
+	r.newClient = func(config Config) (*Client, error) {
+		time.Sleep(10 * time.Millisecond)
+		return newClient(config)
+	}
+
+	// reloads are serialized, so the later ones reuse the client of the first instead of building their own
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.Reload(config); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if want := []string{`com.example.a:{"key":"a"}`}; !reflect.DeepEqual(*loaded, want) {
+		t.Errorf("Reload() loaded = %v, want %v", *loaded, want)
+	}
+}
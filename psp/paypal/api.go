@@ -15,15 +15,106 @@ type API interface {
 	ListDisputes(ctx context.Context) (disputeItems []DisputeItem, error error)
 	ShowDisputeDetails(ctx context.Context, disputeID string) (detail ShowDisputeDetailsResp, error error)
 
+	// 争议处理
+	// https://developer.paypal.com/docs/api/customer-disputes/v1/
+	FilterDisputes(ctx context.Context, req ListDisputesReq) (resp ListDisputesResp, err error)
+	WalkDisputes(ctx context.Context, req ListDisputesReq, fn func(page ListDisputesResp) error) error
+	AcceptClaim(ctx context.Context, disputeID string, req AcceptClaimReq) error
+	MakeOffer(ctx context.Context, disputeID string, req MakeOfferReq) error
+	ProvideEvidence(ctx context.Context, disputeID string, req ProvideEvidenceReq) error
+	SendDisputeMessage(ctx context.Context, disputeID string, message string) error
+	EscalateDispute(ctx context.Context, disputeID string, note string) error
+	AcknowledgeReturnItem(ctx context.Context, disputeID string, req AcknowledgeReturnItemReq) error
+
 	// 交易查询
 	//https://developer.paypal.com/docs/api/transaction-search/v1/#transactions_get
 	GetTransaction(ctx context.Context, transactionID string, startTime, endTime time.Time) (transaction TransactionInfo, err error)
 	GetRefundTransaction(ctx context.Context, startTime, endTime time.Time) (transactions []TransactionInfo, err error)
+	WalkTransactions(ctx context.Context, req TransactionSearchReq, fn func(page TransactionSearchResp) error) error
+	SearchTransactions(ctx context.Context, req TransactionSearchReq, startTime, endTime time.Time, fn func(details []TransactionDetail) error) error
+	GetBalances(ctx context.Context, currencyCode string, asOfTime time.Time) (resp BalancesResp, err error)
+
+	// 订单
+	// https://developer.paypal.com/docs/api/orders/v2/
+	CreateOrder(ctx context.Context, req CreateOrderReq) (order Order, err error)
+	GetOrder(ctx context.Context, orderID string) (order Order, err error)
+	PatchOrder(ctx context.Context, orderID string, operations []PatchOperation) error
+	AuthorizeOrder(ctx context.Context, orderID string, requestID string) (order Order, err error)
+	CaptureOrder(ctx context.Context, orderID string, requestID string) (order Order, err error)
+
+	// 扣款、授权与退款
+	// https://developer.paypal.com/docs/api/payments/v2/
+	GetCapture(ctx context.Context, captureID string) (capture Capture, err error)
+	RefundCapture(ctx context.Context, captureID string, req RefundCaptureReq) (refund Refund, err error)
+	GetRefund(ctx context.Context, refundID string) (refund Refund, err error)
+	GetAuthorization(ctx context.Context, authorizationID string) (authorization Authorization, err error)
+	CaptureAuthorization(ctx context.Context, authorizationID string, req CaptureAuthorizationReq) (capture Capture, err error)
+	VoidAuthorization(ctx context.Context, authorizationID string) error
+	ReauthorizeAuthorization(ctx context.Context, authorizationID string, req ReauthorizeReq) (authorization Authorization, err error)
+
+	// webhook
+	VerifyWebhookSignature(ctx context.Context, req VerifyWebhookSignatureReq) (resp VerifyWebhookSignatureResp, err error)
+	CreateWebhook(ctx context.Context, url string, eventTypes []string) (webhook Webhook, err error)
+	ListWebhooks(ctx context.Context) (webhooks []Webhook, err error)
+	GetWebhook(ctx context.Context, webhookID string) (webhook Webhook, err error)
+	UpdateWebhook(ctx context.Context, webhookID string, url string, eventTypes []string) (webhook Webhook, err error)
+	DeleteWebhook(ctx context.Context, webhookID string) error
+	ListEventTypes(ctx context.Context) (eventTypes []EventType, err error)
+	SimulateEvent(ctx context.Context, req SimulateEventReq) (event WebhookEvent, err error)
+	EnsureWebhook(ctx context.Context, url string, eventTypes []string) (webhook Webhook, err error)
+
+	// 订阅
+	CreateProduct(ctx context.Context, req CreateProductReq) (product Product, err error)
+	ListProducts(ctx context.Context, req ListReq) (resp ListProductsResp, err error)
+	WalkProducts(ctx context.Context, req ListReq, fn func(page ListProductsResp) error) error
+	GetProduct(ctx context.Context, productID string) (product Product, err error)
+	UpdateProduct(ctx context.Context, productID string, operations []PatchOperation) error
+	CreatePlan(ctx context.Context, req CreatePlanReq) (plan Plan, err error)
+	ListPlans(ctx context.Context, req ListPlansReq) (resp ListPlansResp, err error)
+	WalkPlans(ctx context.Context, req ListPlansReq, fn func(page ListPlansResp) error) error
+	GetPlan(ctx context.Context, planID string) (plan Plan, err error)
+	UpdatePlan(ctx context.Context, planID string, operations []PatchOperation) error
+	ActivatePlan(ctx context.Context, planID string) error
+	DeactivatePlan(ctx context.Context, planID string) error
+	UpdatePlanPricing(ctx context.Context, planID string, pricingSchemes []PricingSchemeUpdate) error
+	CreateSubscription(ctx context.Context, req CreateSubscriptionReq) (subscription Subscription, err error)
+	GetSubscription(ctx context.Context, subscriptionID string) (subscription Subscription, err error)
+	ReviseSubscription(ctx context.Context, subscriptionID string, req ReviseSubscriptionReq) (resp ReviseSubscriptionResp, err error)
+	SuspendSubscription(ctx context.Context, subscriptionID string, reason string) error
+	ActivateSubscription(ctx context.Context, subscriptionID string, reason string) error
+	CancelSubscription(ctx context.Context, subscriptionID string, reason string) error
+	ListSubscriptionTransactions(ctx context.Context, subscriptionID string, startTime, endTime time.Time) (transactions []SubscriptionTransaction, err error)
+
+	// 付款
+	// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/
+	CreatePayout(ctx context.Context, req CreatePayoutReq) (batch PayoutBatch, err error)
+	GetPayoutBatch(ctx context.Context, payoutBatchID string, req ListReq) (batch PayoutBatch, err error)
+	WalkPayoutBatch(ctx context.Context, payoutBatchID string, req ListReq, fn func(page PayoutBatch) error) error
+	GetPayoutItem(ctx context.Context, payoutItemID string) (item PayoutItemDetail, err error)
+	CancelPayoutItem(ctx context.Context, payoutItemID string) (item PayoutItemDetail, err error)
+
+	// 支付方式保存
+	// https://developer.paypal.com/docs/api/payment-tokens/v3/
+	CreateSetupToken(ctx context.Context, req CreateSetupTokenReq) (token SetupToken, err error)
+	GetSetupToken(ctx context.Context, setupTokenID string) (token SetupToken, err error)
+	CreatePaymentToken(ctx context.Context, setupTokenID string, requestID string) (token PaymentToken, err error)
+	GetPaymentToken(ctx context.Context, paymentTokenID string) (token PaymentToken, err error)
+	ListPaymentTokens(ctx context.Context, req ListPaymentTokensReq) (resp ListPaymentTokensResp, err error)
+	WalkPaymentTokens(ctx context.Context, req ListPaymentTokensReq, fn func(page ListPaymentTokensResp) error) error
+	DeletePaymentToken(ctx context.Context, paymentTokenID string) error
+	ChargePaymentToken(ctx context.Context, token PaymentToken, req CreateOrderReq) (order Order, err error)
 
 	//NVP
 	SetExpressCheckout(ctx context.Context, req SetExpressCheckoutReq) (SetExpressCheckoutResp , error)
 	GetExpressCheckoutDetails(ctx context.Context, req GetExpressCheckoutDetailsReq) (GetExpressCheckoutDetailsResp , error)
-	DoExpressCheckoutPayment(ctx context.Context, req GetExpressCheckoutDetailsReq) (GetExpressCheckoutDetailsResp , error)
+	DoExpressCheckoutPayment(ctx context.Context, req GetExpressCheckoutDetailsReq) (DoExpressCheckoutPaymentResp , error)
+	CreateBillingAgreement(ctx context.Context, req CreateBillingAgreementReq) (CreateBillingAgreementResp, error)
+	DoReferenceTransaction(ctx context.Context, req DoReferenceTransactionReq) (DoReferenceTransactionResp, error)
+	BAUpdate(ctx context.Context, referenceID string) (BillAgreementUpdateResp, error)
+	BillAgreementUpdate(ctx context.Context, req BillAgreementUpdateReq) (BillAgreementUpdateResp, error)
+	RefundTransaction(ctx context.Context, req RefundTransactionReq) (RefundTransactionResp, error)
+	GetTransactionDetails(ctx context.Context, transactionID string) (GetTransactionDetailsResp, error)
+	TransactionSearch(ctx context.Context, req NVPTransactionSearchReq) (NVPTransactionSearchResp, error)
 }
 
 
@@ -0,0 +1,43 @@
+package paypal
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const balancesPath = "/v1/reporting/balances" // https://developer.paypal.com/docs/api/transaction-search/v1/#balances_get
+
+// Balance is the balance of the account in a currency.
+type Balance struct {
+	Currency         string `json:"currency"`
+	Primary          bool   `json:"primary"`
+	TotalBalance     Money  `json:"total_balance"`
+	AvailableBalance Money  `json:"available_balance"`
+	WithheldBalance  Money  `json:"withheld_balance"`
+}
+
+type BalancesResp struct {
+	Balances        []Balance `json:"balances"`
+	AccountID       string    `json:"account_id"`
+	AsOfTime        time.Time `json:"as_of_time"`
+	LastRefreshTime time.Time `json:"last_refresh_time"`
+}
+
+type getBalancesReq struct {
+	AsOfTime     string `url:"as_of_time,omitempty"`
+	CurrencyCode string `url:"currency_code,omitempty"`
+}
+
+// GetBalances returns the balances of all currencies, or of currencyCode if it is not empty,
+// at asOfTime, or at the last refresh time if asOfTime is zero.
+// The balances are refreshed every few hours like the transactions.
+func (c *Client) GetBalances(ctx context.Context, currencyCode string, asOfTime time.Time) (resp BalancesResp, err error) {
+	req := getBalancesReq{CurrencyCode: currencyCode}
+	if !asOfTime.IsZero() {
+		req.AsOfTime = asOfTime.UTC().Format(time.RFC3339)
+	}
+
+	err = c.do(ctx, "GetBalances", request{method: http.MethodGet, path: balancesPath, query: req}, &resp)
+	return resp, err
+}
@@ -32,18 +32,26 @@ const (
 	nvp                    = "/nvp" // https://developer.paypal.com/docs/nvp-soap-api/nvp/
 
 	// enum
-	disputeStateRESOLVED = "RESOLVED"
-	linkReasonTypeSelf   = "self" //https://www.iana.org/assignments/link-relations/link-relations.xhtml
-	linkReasonTypeNext   = "next"
-	refundByUser         = "RESOLVED_BUYER_FAVOUR" // https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_get
+	disputeStateRESOLVED      = "RESOLVED"
+	linkReasonTypeSelf        = "self" //https://www.iana.org/assignments/link-relations/link-relations.xhtml
+	linkReasonTypeNext        = "next"
+	linkReasonTypeApprove     = "approve"
+	linkReasonTypePayerAction = "payer-action"
+	refundByUser              = "RESOLVED_BUYER_FAVOUR" // https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_get
 
 )
 
 type Client struct {
-	client        *sling.Sling
-	config        Config
-	token         string
-	tokenExpireAt time.Time
+	client *sling.Sling
+	config Config
+	tokens *tokenProvider
+	// limiter throttles the page requests of the Walk* methods.
+	limiter Limiter
+
+	// apiClient sends NVP requests with the API certificate, apiErr is the error of loading the certificate.
+	apiClient     *sling.Sling
+	apiHTTPClient *http.Client
+	apiErr        error
 }
 
 type Config struct {
@@ -54,46 +62,52 @@ type Config struct {
 }
 
 // APICredentials https://developer.paypal.com/docs/nvp-soap-api/apiCredentials/#api-signatures
+// Accounts with an API certificate set Certificate and PrivateKey in PEM instead of Signature.
 type APICredentials struct {
-	Username  string
-	Password  string
-	Signature string
+	Username  string `yaml:"username"`
+	Password  string `yaml:"password"`
+	Signature string `yaml:"signature"`
+
+	Certificate string `yaml:"certificate"`
+	PrivateKey  string `yaml:"private_key"`
+
+	// Endpoint of NVP requests, e.g. NVPCertificateEndpoint, defaults to Config.Host + /nvp.
+	Endpoint string `yaml:"endpoint"`
+	// SOAPEndpoint returned by Client.SOAPClient, defaults to the endpoint matching the credentials.
+	SOAPEndpoint string `yaml:"soap_endpoint"`
 }
 
 func NewClient(c Config) *Client {
 	hc := httpx.NewClient()
-	client := sling.New().Client(hc)
-	return &Client{
-		client: client,
-		config: c,
+	client := &Client{config: c}
+	client.tokens = newTokenProvider(c.Host+"|"+c.ClientID, client.getAccessToken)
+	hc.Transport = &tokenRetryTransport{base: hc.Transport, tokens: client.tokens}
+	client.client = sling.New().Client(hc)
+
+	client.apiHTTPClient, client.apiErr = newAPIHTTPClient(c.NVPAndSOAPAPICredentials)
+	if client.apiErr == nil {
+		client.apiClient = sling.New().Client(client.apiHTTPClient)
 	}
+	return client
 }
 
 // make sure Client implement API interface
 var _ API = (*Client)(nil)
 
-// GetAccessToken always get a token without expire.
-func (c *Client) GetAccessToken(ctx context.Context) (string, error) {
-	if c.token != "" && c.tokenExpireAt.After(time.Now().Add(-getTokenBeforeExpire)) {
-		return c.token, nil
-	}
-
-	token, expireAt, err := c.getAccessToken(ctx)
-	if err != nil {
-		return "", err
-	}
-
-	c.token = token
-	c.tokenExpireAt = expireAt
+// SetTokenCache shares the access token with other instances of the same app through cache.
+func (c *Client) SetTokenCache(cache TokenCache) {
+	c.tokens.setCache(cache)
+}
 
-	log.L(ctx).Info("get token success", zap.String("expireAt", expireAt.Format(time.RFC3339)))
-	return token, nil
+// GetAccessToken returns a cached token valid for at least 30s, it is safe for concurrent use.
+func (c *Client) GetAccessToken(ctx context.Context) (string, error) {
+	return c.tokens.get(ctx)
 }
 
 // getAccessToken: In general, access tokens have a life of 15 minutes or eight hours depending on the scopes associated.
 func (c *Client) getAccessToken(ctx context.Context) (token string, expireAt time.Time, err error) {
 	path := c.config.Host + getAccessTokenPath
-	req, err := http.NewRequest(http.MethodPost, path, bytes.NewBuffer([]byte("grant_type=client_credentials")))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, bytes.NewBuffer([]byte("grant_type=client_credentials")))
 	if err != nil {
 		log.L(ctx).Error("GetAccessToken", zap.String("err", err.Error()))
 		return "", time.Now(), err
@@ -102,9 +116,12 @@ func (c *Client) getAccessToken(ctx context.Context) (token string, expireAt tim
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	var resBody AccessTokeResp
-	var errResp ErrorResp
-	_, err = c.client.Do(req, &resBody, &errResp)
-	if err = errResp.Err(err); err != nil {
+	var apiErr APIError
+	resp, err := c.client.Do(req, &resBody, &apiErr)
+	if err == nil && resp.StatusCode >= http.StatusMultipleChoices {
+		err = newAPIError(resp, apiErr)
+	}
+	if err != nil {
 		log.L(ctx).Error("GetAccessToken", zap.String("err", err.Error()))
 		return "", time.Now(), err
 	}
@@ -117,157 +134,39 @@ func (c *Client) getAccessToken(ctx context.Context) (token string, expireAt tim
 }
 
 // ListDisputes list all resolve dispute.
-func (c *Client) ListDisputes(ctx context.Context) (disputeItems []DisputeItem, error error) {
-	accessToken, err := c.GetAccessToken(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	path := c.config.Host + listDisputesPath
-	var firstPageDisputes ListDisputesResp
-	var errResp ErrorResp
-	_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-		QueryStruct(ListDisputesReq{DisputeState: disputeStateRESOLVED, PageSize: maxPageSize}).
-		Path(path).Receive(&firstPageDisputes, &errResp)
-
-	if err = errResp.Err(err); err != nil {
-		log.S(ctx).Errorw("ListDisputes", "err", err)
-		return nil, err
-	}
-
-	return c.listAllDisputes(ctx, accessToken, firstPageDisputes)
-}
-
-// listFirstPageDisputes ...
-func (c *Client) listFirstPageDisputes(ctx context.Context) (disputeItems []DisputeItem, nextPageUrl string, error error) {
-	accessToken, err := c.GetAccessToken(ctx)
-	if err != nil {
-		return nil, "", err
-	}
-
-	path := c.config.Host + listDisputesPath
-	var firstPageDisputes ListDisputesResp
-	var errResp ErrorResp
-	_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-		QueryStruct(ListDisputesReq{DisputeState: disputeStateRESOLVED, PageSize: maxPageSize}).
-		Path(path).Receive(&firstPageDisputes, &errResp)
-
-	if err = errResp.Err(err); err != nil {
-		log.S(ctx).Errorw("listFirstPageDisputes", "err", err)
-		return nil, "", err
-	}
-
-	return firstPageDisputes.Items, findNextPageToken(firstPageDisputes), nil
+func (c *Client) ListDisputes(ctx context.Context) (disputeItems []DisputeItem, err error) {
+	err = c.WalkDisputes(ctx, ListDisputesReq{DisputeState: disputeStateRESOLVED, PageSize: maxPageSize}, func(page ListDisputesResp) error {
+		disputeItems = append(disputeItems, page.Items...)
+		return nil
+	})
+	return disputeItems, err
 }
 
-// listFirstPageDisputes ...
-func (c *Client) listFirstPageDisputesV2(ctx context.Context, startTime, endTime time.Time) (disputeItems []DisputeItem, nextPageUrl string, error error) {
-	accessToken, err := c.GetAccessToken(ctx)
-	if err != nil {
-		return nil, "", err
-	}
-
-	path := c.config.Host + listDisputesPath
-	var firstPageDisputes ListDisputesResp
-	var errResp ErrorResp
-	_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-		QueryStruct(ListDisputesReq{DisputeState: disputeStateRESOLVED, PageSize: maxPageSize,
-			UpdateTimeAfter: startTime.Format(timeFmt), UpdateTimeBefore: endTime.Format(timeFmt)}).
-		Path(path).Receive(&firstPageDisputes, &errResp)
-
-	if err = errResp.Err(err); err != nil {
-		log.S(ctx).Errorw("listFirstPageDisputesV2", "err", err)
-
-		return nil, "", err
-	}
-
-	return firstPageDisputes.Items, findNextPageToken(firstPageDisputes), nil
-}
-
-// ListDisputes list all resolve dispute.
+// ListOnePageDisputes list one page of resolved disputes, the first page is listed if pagePageToken is empty.
 func (c *Client) ListOnePageDisputes(ctx context.Context, pagePageToken string) (disputeItems []DisputeItem, nextPageToken string, error error) {
-	if pagePageToken == "" {
-		return c.listFirstPageDisputes(ctx)
-	}
-
-	accessToken, err := c.GetAccessToken(ctx)
-	if err != nil {
-		return nil, "", err
-	}
-
-	var nextPageDisputes ListDisputesResp
-	var errResp ErrorResp
-
-	path := c.config.Host + listDisputesPath
-	_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-		QueryStruct(ListDisputesReq{DisputeState: disputeStateRESOLVED, PageSize: maxPageSize, NextPageToken: pagePageToken}).
-		Path(path).Receive(&nextPageDisputes, &errResp)
-	if err = errResp.Err(err); err != nil {
-		log.S(ctx).Errorw("ListOnePageDisputes", "err", err)
-
-		return nil, "", err
-	}
-
-	return nextPageDisputes.Items, findNextPageToken(nextPageDisputes), nil
+	return c.listOnePageDisputes(ctx, "ListOnePageDisputes",
+		ListDisputesReq{DisputeState: disputeStateRESOLVED, PageSize: maxPageSize, NextPageToken: pagePageToken})
 }
 
 func (c *Client) ListOnePageDisputesV2(ctx context.Context, pagePageToken string, startTime, endTime time.Time) (disputeItems []DisputeItem, nextPageToken string, error error) {
-	if pagePageToken == "" {
-		return c.listFirstPageDisputesV2(ctx, startTime, endTime)
-	}
+	return c.listOnePageDisputes(ctx, "ListOnePageDisputesV2",
+		ListDisputesReq{DisputeState: disputeStateRESOLVED, PageSize: maxPageSize, NextPageToken: pagePageToken,
+			UpdateTimeAfter: startTime.Format(timeFmt), UpdateTimeBefore: endTime.Format(timeFmt)})
+}
 
-	accessToken, err := c.GetAccessToken(ctx)
+func (c *Client) listOnePageDisputes(ctx context.Context, name string, req ListDisputesReq) (disputeItems []DisputeItem, nextPageToken string, err error) {
+	var disputes ListDisputesResp
+	err = c.do(ctx, name, request{method: http.MethodGet, path: listDisputesPath, query: req}, &disputes)
 	if err != nil {
 		return nil, "", err
 	}
 
-	var nextPageDisputes ListDisputesResp
-	var errResp ErrorResp
-
-	path := c.config.Host + listDisputesPath
-	_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-		QueryStruct(
-			ListDisputesReq{DisputeState: disputeStateRESOLVED, PageSize: maxPageSize, NextPageToken: pagePageToken,
-				UpdateTimeAfter: startTime.Format(timeFmt), UpdateTimeBefore: endTime.Format(timeFmt)}).
-		Path(path).Receive(&nextPageDisputes, &errResp)
-	if err = errResp.Err(err); err != nil {
-		log.S(ctx).Errorw("ListOnePageDisputesV2", "err", err)
-
-		return nil, "", err
-	}
-
-	return nextPageDisputes.Items, findNextPageToken(nextPageDisputes), nil
-}
-
-func (c *Client) listAllDisputes(ctx context.Context, accessToken string, firstPageDisputes ListDisputesResp) (disputeItems []DisputeItem, err error) {
-	allDisputeItems := make([]DisputeItem, 0, len(firstPageDisputes.Items))
-	thisPage := firstPageDisputes
-
-	for {
-		path := findNextPageToken(thisPage)
-		if path == "" {
-			allDisputeItems = append(allDisputeItems, thisPage.Items...)
-			break
-		}
-
-		var nextPageDisputes ListDisputesResp
-		var errResp ErrorResp
-		_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-			Path(path).Receive(&nextPageDisputes, &errResp)
-		if err = errResp.Err(err); err != nil {
-			log.S(ctx).Errorw("listAllDisputes", "err", err)
-
-			break
-		}
-
-		thisPage = nextPageDisputes
-	}
-
-	return allDisputeItems, nil
+	return disputes.Items, findNextPageToken(disputes), nil
 }
 
+// findNextPageToken returns the next_page_token of the next link, a page may have less than page_size items before the last page.
 func findNextPageToken(disputes ListDisputesResp) string {
-	if len(disputes.Items) < maxPageSize {
+	if len(disputes.Items) == 0 {
 		return ""
 	}
 
@@ -286,22 +185,8 @@ func findNextPageToken(disputes ListDisputesResp) string {
 
 // ShowDisputeDetails get dispute detail by disputeID.
 func (c *Client) ShowDisputeDetails(ctx context.Context, disputeID string) (detail ShowDisputeDetailsResp, error error) {
-	accessToken, err := c.GetAccessToken(ctx)
-	if err != nil {
-		return detail, err
-	}
-
-	path := c.config.Host + showDisputeDetailsPath + disputeID
-	var errResp ErrorResp
-	_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-		Path(path).Receive(&detail, &errResp)
-	if err = errResp.Err(err); err != nil {
-		log.S(ctx).Errorw("ShowDisputeDetails", "err", err)
-
-		return detail, err
-	}
-
-	return detail, nil
+	err := c.do(ctx, "ShowDisputeDetails", request{method: http.MethodGet, path: showDisputeDetailsPath + disputeID}, &detail)
+	return detail, err
 }
 
 // GetUserRefundDispute get user refund dispute if this dispute refunded by user.
@@ -381,8 +266,8 @@ func (c *Client) FiltrateUserRefundDispute(ctx context.Context, disputes []Dispu
 	return userRefundDisputes
 }
 
-// GetTransaction get first transaction by transactionID
-// time range between startTime and endTime must less than 31 days.
+// GetTransaction get first transaction by transactionID between startTime and endTime,
+// a time range longer than 31 days is searched by windows from the oldest.
 //https://developer.paypal.com/docs/integration/direct/transaction-search/#list-transactions
 /*
 curl example
@@ -395,77 +280,35 @@ func (c *Client) GetTransaction(ctx context.Context, transactionID string, start
 		return transaction, errors.New("transactionID is zero")
 	}
 
-	accessToken, err := c.GetAccessToken(ctx)
+	var found bool
+	err = c.SearchTransactions(ctx, TransactionSearchReq{TransactionID: transactionID, PageSize: 100}, startTime, endTime,
+		func(details []TransactionDetail) error {
+			if len(details) == 0 {
+				return nil
+			}
+			transaction, found = details[0].TransactionInfo, true
+			return ErrStopPaging
+		})
 	if err != nil {
 		return transaction, err
 	}
 
-	var resp TransactionSearchResp
-	var errResp ErrorResp
-	path := c.config.Host + getTransactionPath
-	_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-		Path(path).QueryStruct(TransactionSearchReq{TransactionID: transactionID, StartDate: startTime.Format(timeFmt), EndDate: endTime.Format(timeFmt), Fields: "all", PageSize: 100, Page: 1}).
-		Receive(&resp, &errResp)
-
-	if err = errResp.Err(err); err != nil {
-		log.S(ctx).Errorw("GetTransaction", "path", path,"err",err)
-
-		return transaction, err
-	}
-
-	if len(resp.TransactionDetails) > 0 {
-		return resp.TransactionDetails[0].TransactionInfo, nil
+	if found {
+		return transaction, nil
 	}
 
 	return transaction, errors.New("transaction not fund")
 }
 
+// GetRefundTransaction lists all refund transactions between startTime and endTime.
 func (c *Client) GetRefundTransaction(ctx context.Context, startTime, endTime time.Time) (transactions []TransactionInfo, err error) {
-	transaction, totalPage, err := c.getRefundTransactionByPage(ctx, startTime, endTime, 1)
-	if err != nil {
-		return transactions, err
-	}
-
 	transactions = make([]TransactionInfo, 0, maxPageSizeForTranscationSearch)
-	transactions = append(transactions, transaction...)
-	for page := 2; page <= totalPage; page++ {
-		transaction, _, err := c.getRefundTransactionByPage(ctx, startTime, endTime, page)
-		if err != nil {
-			log.S(ctx).Errorw("getRefundTransactionByPage", "err",err)
-
-			return transactions, err
-		}
-
-		transactions = append(transactions, transaction...)
-	}
-
-	return transactions, nil
-}
-
-func (c *Client) getRefundTransactionByPage(ctx context.Context, startTime, endTime time.Time, page int) (transactions []TransactionInfo, totalPage int, err error) {
-	accessToken, err := c.GetAccessToken(ctx)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	var resp TransactionSearchResp
-	var errResp ErrorResp
-	path := c.config.Host + getTransactionPath
-	_, err = c.client.Set("Authorization", "Bearer "+accessToken).
-		Path(path).QueryStruct(
-		TransactionSearchReq{
-			StartDate: startTime.Format(timeFmt), EndDate: endTime.Format(timeFmt), Fields: "all", PageSize: maxPageSizeForTranscationSearch, Page: page,
-			TransactionStatus: TransactionRefund}).
-		Receive(&resp, &errResp)
-
-	if err = errResp.Err(err); err != nil {
-		return nil, 0, err
-	}
-
-	transactions = make([]TransactionInfo, 0, len(resp.TransactionDetails))
-	for _, detail := range resp.TransactionDetails {
-		transactions = append(transactions, detail.TransactionInfo)
-	}
-
-	return transactions, resp.TotalPages, nil
+	err = c.SearchTransactions(ctx, TransactionSearchReq{TransactionStatus: TransactionRefund}, startTime, endTime,
+		func(details []TransactionDetail) error {
+			for _, detail := range details {
+				transactions = append(transactions, detail.TransactionInfo)
+			}
+			return nil
+		})
+	return transactions, err
 }
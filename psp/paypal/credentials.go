@@ -0,0 +1,104 @@
+package paypal
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+
+	"github.com/linhoi/gopay/common/httpx"
+)
+
+// NVP and SOAP endpoints, accounts with API certificates must call the certificate endpoints.
+// https://developer.paypal.com/api/nvp-soap/gs-PayPalAPIs/#api-endpoints
+const (
+	NVPSignatureEndpoint          = "https://api-3t.paypal.com/nvp"
+	NVPCertificateEndpoint        = "https://api.paypal.com/nvp"
+	NVPSignatureSandboxEndpoint   = "https://api-3t.sandbox.paypal.com/nvp"
+	NVPCertificateSandboxEndpoint = "https://api.sandbox.paypal.com/nvp"
+
+	SOAPSignatureEndpoint          = "https://api-3t.paypal.com/2.0/"
+	SOAPCertificateEndpoint        = "https://api.paypal.com/2.0/"
+	SOAPSignatureSandboxEndpoint   = "https://api-3t.sandbox.paypal.com/2.0/"
+	SOAPCertificateSandboxEndpoint = "https://api.sandbox.paypal.com/2.0/"
+)
+
+// sandboxHosts are the hosts of the sandbox REST, NVP and SOAP APIs,
+// a Config.Host of any other host, e.g. a proxy, must set the endpoints of APICredentials for the sandbox.
+var sandboxHosts = map[string]bool{
+	"api-m.sandbox.paypal.com":  true,
+	"api.sandbox.paypal.com":    true,
+	"api-3t.sandbox.paypal.com": true,
+}
+
+// isSandbox reports whether Config.Host is a sandbox host of paypal.
+func (c *Client) isSandbox() bool {
+	u, err := url.Parse(c.config.Host)
+	return err == nil && sandboxHosts[u.Hostname()]
+}
+
+// UseCertificate reports whether the credentials authenticate with an API certificate instead of a signature.
+func (a APICredentials) UseCertificate() bool {
+	return a.Certificate != ""
+}
+
+// newAPIHTTPClient returns the http client of NVP and SOAP calls,
+// it presents the API certificate in the TLS handshake if the credentials have one.
+func newAPIHTTPClient(credentials APICredentials) (*http.Client, error) {
+	hc := httpx.NewClient()
+	if !credentials.UseCertificate() {
+		return hc, nil
+	}
+
+	certificate, err := tls.X509KeyPair([]byte(credentials.Certificate), []byte(credentials.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	hc.Transport.(*http.Transport).TLSClientConfig = &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	return hc, nil
+}
+
+// nvpEndpoint returns APICredentials.Endpoint, or by default the certificate endpoint matching the sandbox host of Config.Host
+// for credentials with an API certificate, and Config.Host + /nvp for a signature.
+func (c *Client) nvpEndpoint() string {
+	credentials := c.config.NVPAndSOAPAPICredentials
+	switch {
+	case credentials.Endpoint != "":
+		return credentials.Endpoint
+	case credentials.UseCertificate() && c.isSandbox():
+		return NVPCertificateSandboxEndpoint
+	case credentials.UseCertificate():
+		return NVPCertificateEndpoint
+	default:
+		return c.config.Host + nvp
+	}
+}
+
+// SOAPClient returns the http client with the API certificate and the SOAP endpoint,
+// for the SOAP operations Client does not wrap.
+// The endpoint is APICredentials.SOAPEndpoint, or the one matching the credentials and the sandbox host of Config.Host by default.
+func (c *Client) SOAPClient() (*http.Client, string, error) {
+	if c.apiErr != nil {
+		return nil, "", c.apiErr
+	}
+
+	credentials := c.config.NVPAndSOAPAPICredentials
+	if credentials.SOAPEndpoint != "" {
+		return c.apiHTTPClient, credentials.SOAPEndpoint, nil
+	}
+
+	sandbox := c.isSandbox()
+	switch {
+	case credentials.UseCertificate() && sandbox:
+		return c.apiHTTPClient, SOAPCertificateSandboxEndpoint, nil
+	case credentials.UseCertificate():
+		return c.apiHTTPClient, SOAPCertificateEndpoint, nil
+	case sandbox:
+		return c.apiHTTPClient, SOAPSignatureSandboxEndpoint, nil
+	default:
+		return c.apiHTTPClient, SOAPSignatureEndpoint, nil
+	}
+}
@@ -0,0 +1,142 @@
+package paypal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newAPICertificate returns a self signed API certificate and its private key in PEM.
+func newAPICertificate(t *testing.T, username string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: username},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestClient_NVPCertificate(t *testing.T) {
+	var form url.Values
+	var peer string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = r.PostForm
+		if len(r.TLS.PeerCertificates) > 0 {
+			peer = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+		_, _ = w.Write([]byte(url.Values{"ACK": {AckSuccess}, "TOKEN": {"EC-38A5689031140083A"}}.Encode()))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	certificate, key := newAPICertificate(t, "merchant_api1.example.com")
+	c := NewClient(Config{NVPAndSOAPAPICredentials: APICredentials{
+		Username:    "merchant_api1.example.com",
+		Password:    "pwd",
+		Certificate: certificate,
+		PrivateKey:  key,
+		Endpoint:    server.URL + "/nvp",
+	}})
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	c.apiHTTPClient.Transport.(*http.Transport).TLSClientConfig.RootCAs = roots
+
+	resp, err := c.GetExpressCheckoutDetails(context.Background(), GetExpressCheckoutDetailsReq{Token: "EC-38A5689031140083A"})
+	if err != nil || resp.Token != "EC-38A5689031140083A" {
+		t.Fatalf("GetExpressCheckoutDetails() got = %+v, err = %v", resp, err)
+	}
+	if peer != "merchant_api1.example.com" {
+		t.Errorf("GetExpressCheckoutDetails() client certificate = %q", peer)
+	}
+	if _, ok := form["SIGNATURE"]; ok || form.Get("USER") != "merchant_api1.example.com" {
+		t.Errorf("GetExpressCheckoutDetails() form = %v", form)
+	}
+
+	if _, endpoint, err := c.SOAPClient(); err != nil || endpoint != SOAPCertificateEndpoint {
+		t.Errorf("SOAPClient() endpoint = %v, err = %v", endpoint, err)
+	}
+}
+
+func TestClient_NVPInvalidCertificate(t *testing.T) {
+	c := NewClient(Config{NVPAndSOAPAPICredentials: APICredentials{Username: "user", Certificate: "invalid", PrivateKey: "invalid"}})
+
+	if _, err := c.GetExpressCheckoutDetails(context.Background(), GetExpressCheckoutDetailsReq{Token: "EC-38A5689031140083A"}); err == nil {
+		t.Errorf("GetExpressCheckoutDetails() err = nil, want certificate error")
+	}
+	if _, _, err := c.SOAPClient(); err == nil {
+		t.Errorf("SOAPClient() err = nil, want certificate error")
+	}
+}
+
+func TestClient_SOAPClient(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{name: "signature", config: Config{Host: "https://api-m.paypal.com"}, want: SOAPSignatureEndpoint},
+		{name: "sandbox signature", config: Config{Host: "https://api-3t.sandbox.paypal.com/"}, want: SOAPSignatureSandboxEndpoint},
+		{name: "proxy", config: Config{Host: "https://paypal-sandbox-proxy.example.com"}, want: SOAPSignatureEndpoint},
+		{name: "configured", config: Config{NVPAndSOAPAPICredentials: APICredentials{SOAPEndpoint: "https://soap.example.com/2.0/"}}, want: "https://soap.example.com/2.0/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc, endpoint, err := NewClient(tt.config).SOAPClient()
+			if err != nil || hc == nil || endpoint != tt.want {
+				t.Errorf("SOAPClient() got = %v, err = %v, want %v", endpoint, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_nvpEndpoint(t *testing.T) {
+	certificate := APICredentials{Certificate: "-----BEGIN CERTIFICATE-----"}
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{name: "signature", config: Config{Host: "https://api-3t.paypal.com"}, want: "https://api-3t.paypal.com/nvp"},
+		{name: "certificate", config: Config{Host: "https://api-m.paypal.com", NVPAndSOAPAPICredentials: certificate}, want: NVPCertificateEndpoint},
+		{name: "sandbox certificate", config: Config{Host: "https://api-m.sandbox.paypal.com", NVPAndSOAPAPICredentials: certificate}, want: NVPCertificateSandboxEndpoint},
+		{name: "proxy certificate", config: Config{Host: "https://paypal-sandbox-proxy.example.com", NVPAndSOAPAPICredentials: certificate}, want: NVPCertificateEndpoint},
+		{name: "configured", config: Config{NVPAndSOAPAPICredentials: APICredentials{Certificate: "-----BEGIN CERTIFICATE-----", Endpoint: "https://nvp.example.com/nvp"}}, want: "https://nvp.example.com/nvp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{config: tt.config}
+			if got := c.nvpEndpoint(); got != tt.want {
+				t.Errorf("nvpEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
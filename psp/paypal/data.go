@@ -27,11 +27,14 @@ type AccessTokeResp struct {
 }
 
 type ListDisputesReq struct {
-	DisputeState     string `url:"dispute_state"`
-	PageSize         int    `url:"page_size"`
-	NextPageToken    string `url:"next_page_token,omitempty"`
-	UpdateTimeBefore string `url:"update_time_before,omitempty"`
-	UpdateTimeAfter  string `url:"update_time_after,omitempty"`
+	// DisputeState filters disputes by one or more comma separated states, disputes in any state are listed if empty.
+	DisputeState          string `url:"dispute_state,omitempty"`
+	DisputedTransactionID string `url:"disputed_transaction_id,omitempty"`
+	StartTime             string `url:"start_time,omitempty"`
+	PageSize              int    `url:"page_size,omitempty"`
+	NextPageToken         string `url:"next_page_token,omitempty"`
+	UpdateTimeBefore      string `url:"update_time_before,omitempty"`
+	UpdateTimeAfter       string `url:"update_time_after,omitempty"`
 }
 
 type ListDisputesResp struct {
@@ -78,39 +81,15 @@ type ShowDisputeDetailsResp struct {
 		} `json:"amount_refunded"`
 	} `json:"dispute_outcome"`
 
-	// not necessary now
-
-	//DisputeLifeCycleStage string `json:"dispute_life_cycle_stage"`
-	//DisputeChannel        string `json:"dispute_channel"`
-	//Messages              []struct {
-	//	PostedBy   string    `json:"posted_by"`
-	//	TimePosted time.Time `json:"time_posted"`
-	//	Content    string    `json:"content"`
-	//	Documents  []struct {
-	//		Name string `json:"name"`
-	//		Url  string `json:"url"`
-	//	} `json:"documents"`
-	//} `json:"messages"`
-	//Extensions struct {
-	//	MerchandizeDisputeProperties struct {
-	//		IssueType      string `json:"issue_type"`
-	//		ServiceDetails struct {
-	//			SubReasons  []string `json:"sub_reasons"`
-	//			PurchaseUrl string   `json:"purchase_url"`
-	//		} `json:"service_details"`
-	//	} `json:"merchandize_dispute_properties"`
-	//} `json:"extensions"`
-	//Offer struct {
-	//	BuyerRequestedAmount struct {
-	//		CurrencyCode string `json:"currency_code"`
-	//		Value        string `json:"value"`
-	//	} `json:"buyer_requested_amount"`
-	//} `json:"offer"`
-	//Links []struct {
-	//	Href   string `json:"href"`
-	//	Rel    string `json:"rel"`
-	//	Method string `json:"method"`
-	//} `json:"links"`
+	DisputeLifeCycleStage DisputeLifeCycleStage `json:"dispute_life_cycle_stage"`
+	DisputeChannel        string                `json:"dispute_channel"`
+	SellerResponseDueDate time.Time             `json:"seller_response_due_date"`
+	BuyerResponseDueDate  time.Time             `json:"buyer_response_due_date"`
+	Messages              []DisputeMessage      `json:"messages"`
+	Extensions            DisputeExtensions     `json:"extensions"`
+	Offer                 *DisputeOffer         `json:"offer"`
+	Evidences             []DisputeEvidence     `json:"evidences"`
+	Links                 []HATEOASLink         `json:"links"`
 }
 
 func (s ShowDisputeDetailsResp) GetDisputeIDAndTransactionID() string {
@@ -154,80 +133,27 @@ type TransactionSearchReq struct {
 	StartDate         string            `url:"start_date"`
 	EndDate           string            `url:"end_date"`
 	TransactionStatus TransactionStatus `url:"transaction_status,omitempty"`
+
+	InvoiceID       string `url:"invoice_id,omitempty"`
+	TransactionType string `url:"transaction_type,omitempty"`
+	// TransactionAmount is a range of the gross amount in the lower denomination, see TransactionAmountRange.
+	TransactionAmount     string `url:"transaction_amount,omitempty"`
+	TransactionCurrency   string `url:"transaction_currency,omitempty"`
+	PaymentInstrumentType string `url:"payment_instrument_type,omitempty"`
+	StoreID               string `url:"store_id,omitempty"`
+	TerminalID            string `url:"terminal_id,omitempty"`
+	// BalanceAffectingRecordsOnly is Y to list only the records changing the balance, or N to list all.
+	BalanceAffectingRecordsOnly string `url:"balance_affecting_records_only,omitempty"`
 }
 
 type TransactionSearchResp struct {
-	TransactionDetails []struct {
-		TransactionInfo TransactionInfo `json:"transaction_info"`
-
-		// Not necessary Body Now
-
-		//PayerInfo       struct {
-		//	AccountId     string `json:"account_id"`
-		//	EmailAddress  string `json:"email_address"`
-		//	AddressStatus string `json:"address_status"`
-		//	PayerStatus   string `json:"payer_status"`
-		//	PayerName     struct {
-		//		GivenName         string `json:"given_name"`
-		//		Surname           string `json:"surname"`
-		//		AlternateFullName string `json:"alternate_full_name"`
-		//	} `json:"payer_name"`
-		//	CountryCode string `json:"country_code"`
-		//} `json:"payer_info"`
-		//ShippingInfo struct {
-		//	Name    string `json:"name"`
-		//	Address struct {
-		//		Line1       string `json:"line1"`
-		//		Line2       string `json:"line2"`
-		//		City        string `json:"city"`
-		//		CountryCode string `json:"country_code"`
-		//		PostalCode  string `json:"postal_code"`
-		//	} `json:"address"`
-		//} `json:"shipping_info"`
-		//CartInfo struct {
-		//	ItemDetails []struct {
-		//		ItemCode        string `json:"item_code,omitempty"`
-		//		ItemName        string `json:"item_name"`
-		//		ItemDescription string `json:"item_description,omitempty"`
-		//		ItemQuantity    string `json:"item_quantity"`
-		//		ItemUnitPrice   struct {
-		//			CurrencyCode string `json:"currency_code"`
-		//			Value        string `json:"value"`
-		//		} `json:"item_unit_price"`
-		//		ItemAmount struct {
-		//			CurrencyCode string `json:"currency_code"`
-		//			Value        string `json:"value"`
-		//		} `json:"item_amount"`
-		//		TaxAmounts []struct {
-		//			TaxAmount struct {
-		//				CurrencyCode string `json:"currency_code"`
-		//				Value        string `json:"value"`
-		//			} `json:"tax_amount"`
-		//		} `json:"tax_amounts,omitempty"`
-		//		TotalItemAmount struct {
-		//			CurrencyCode string `json:"currency_code"`
-		//			Value        string `json:"value"`
-		//		} `json:"total_item_amount"`
-		//		InvoiceNumber string `json:"invoice_number"`
-		//	} `json:"item_details"`
-		//} `json:"cart_info"`
-		//StoreInfo struct {
-		//} `json:"store_info"`
-		//AuctionInfo struct {
-		//} `json:"auction_info"`
-		//IncentiveInfo struct {
-		//} `json:"incentive_info"`
-	} `json:"transaction_details"`
-	AccountNumber         string `json:"account_number"`
-	LastRefreshedDatetime string `json:"last_refreshed_datetime"`
-	Page                  int    `json:"page"`
-	TotalItems            int    `json:"total_items"`
-	TotalPages            int    `json:"total_pages"`
-	Links                 []struct {
-		Href   string `json:"href"`
-		Rel    string `json:"rel"`
-		Method string `json:"method"`
-	} `json:"links"`
+	TransactionDetails    []TransactionDetail `json:"transaction_details"`
+	AccountNumber         string              `json:"account_number"`
+	LastRefreshedDatetime string              `json:"last_refreshed_datetime"`
+	Page                  int                 `json:"page"`
+	TotalItems            int                 `json:"total_items"`
+	TotalPages            int                 `json:"total_pages"`
+	Links                 []HATEOASLink       `json:"links"`
 }
 
 type TransactionInfo struct {
@@ -277,43 +203,110 @@ func (t *TransactionInfo) GetInvoiceID() string {
 	return t.CustomField
 }
 
+// SetExpressCheckoutReq https://developer.paypal.com/api/nvp-soap/set-express-checkout-nvp/#request-fields
 type SetExpressCheckoutReq struct {
-	ReturnURL    string `url:"RETURNURL"`
-	Amount       string `url:"PAYMENTREQUEST_0_AMT"`
-	NoShipping   string `url:"NOSHIPPING"`
-	CurrencyCode string `url:"PAYMENTREQUEST_0_CURRENCYCODE"`
-	Desc         string `url:"PAYMENTREQUEST_0_DESC"`
-	Customer     string `url:"PAYMENTREQUEST_0_CUSTOM"`
-	Invoice      string `url:"PAYMENTREQUEST_0_INVNUM"`
-	CancelURL    string `url:"CANCELURL"`
+	ReturnURL  string `url:"RETURNURL"`
+	CancelURL  string `url:"CANCELURL"`
+	NoShipping string `url:"NOSHIPPING"`
+
+	// Amount, CurrencyCode, Desc, Customer and Invoice are the PAYMENTREQUEST_0 of a single payment without items,
+	// PaymentRequests replaces them, they are not sent if PaymentRequests is set.
+	Amount       string `url:"PAYMENTREQUEST_0_AMT,omitempty"`
+	CurrencyCode string `url:"PAYMENTREQUEST_0_CURRENCYCODE,omitempty"`
+	Desc         string `url:"PAYMENTREQUEST_0_DESC,omitempty"`
+	Customer     string `url:"PAYMENTREQUEST_0_CUSTOM,omitempty"`
+	Invoice      string `url:"PAYMENTREQUEST_0_INVNUM,omitempty"`
+
+	// MaxAmount is the expected maximum total of the order, including shipping and tax.
+	MaxAmount          string `url:"MAXAMT,omitempty"`
+	ReqConfirmShipping string `url:"REQCONFIRMSHIPPING,omitempty"`
+	AddrOverride       string `url:"ADDROVERRIDE,omitempty"`
+	Email              string `url:"EMAIL,omitempty"`
+
+	LocaleCode      string `url:"LOCALECODE,omitempty"`
+	LandingPage     string `url:"LANDINGPAGE,omitempty"`
+	SolutionType    string `url:"SOLUTIONTYPE,omitempty"`
+	BrandName       string `url:"BRANDNAME,omitempty"`
+	PageStyle       string `url:"PAGESTYLE,omitempty"`
+	LogoImage       string `url:"LOGOIMG,omitempty"`
+	CartBorderColor string `url:"CARTBORDERCOLOR,omitempty"`
+
+	PaymentRequests   []PaymentRequest   `url:"-"`
+	BillingAgreements []BillingAgreement `url:"-"`
 }
 
-type setExpressCheckoutReq struct {
-	NVPBase
-	SetExpressCheckoutReq
+// NVPResp holds the fields every NVP response has.
+// https://developer.paypal.com/api/nvp-soap/NVPAPIOverview/#responses
+type NVPResp struct {
+	Ack           string `url:"ACK"`
+	CorrelationID string `url:"CORRELATIONID"`
+	Timestamp     string `url:"TIMESTAMP"`
+	Version       string `url:"VERSION"`
+	Build         string `url:"BUILD"`
 }
 
 type SetExpressCheckoutResp struct {
-	Body url.Values
+	NVPResp
+	Token string `url:"TOKEN"`
+
+	Body url.Values `url:"-"`
 }
 
 type NVPBase struct {
-	Method    string `url:"METHOD"`
-	Version   string `url:"VERSION"`
-	User      string `url:"USER"`
-	Pwd       string `url:"PWD"`
-	Signature string `url:"SIGNATURE"`
+	Method  string `url:"METHOD"`
+	Version string `url:"VERSION"`
+	User    string `url:"USER"`
+	Pwd     string `url:"PWD"`
+	// Signature is empty for the credentials with an API certificate.
+	Signature string `url:"SIGNATURE,omitempty"`
 }
 
 type GetExpressCheckoutDetailsReq struct {
 	Token string `url:"TOKEN"`
 }
 
-type getExpressCheckoutDetailsReq struct {
-	NVPBase
-	GetExpressCheckoutDetailsReq
+// GetExpressCheckoutDetailsResp https://developer.paypal.com/api/nvp-soap/get-express-checkout-details-nvp/#response-fields
+type GetExpressCheckoutDetailsResp struct {
+	NVPResp
+	Token          string `url:"TOKEN"`
+	CheckoutStatus string `url:"CHECKOUTSTATUS"`
+	PayerID        string `url:"PAYERID"`
+	PayerStatus    string `url:"PAYERSTATUS"`
+	Email          string `url:"EMAIL"`
+	FirstName      string `url:"FIRSTNAME"`
+	LastName       string `url:"LASTNAME"`
+	CountryCode    string `url:"COUNTRYCODE"`
+
+	Amount        string `url:"PAYMENTREQUEST_0_AMT"`
+	ItemAmount    string `url:"PAYMENTREQUEST_0_ITEMAMT"`
+	CurrencyCode  string `url:"PAYMENTREQUEST_0_CURRENCYCODE"`
+	Desc          string `url:"PAYMENTREQUEST_0_DESC"`
+	Customer      string `url:"PAYMENTREQUEST_0_CUSTOM"`
+	Invoice       string `url:"PAYMENTREQUEST_0_INVNUM"`
+	PaymentAction string `url:"PAYMENTREQUEST_0_PAYMENTACTION"`
+
+	BillingAgreementAcceptedStatus string `url:"BILLINGAGREEMENTACCEPTEDSTATUS"`
+	// PaymentRequests holds all the PAYMENTREQUEST_n groups and their items.
+	PaymentRequests []PaymentRequest `url:"-"`
+
+	Body url.Values `url:"-"`
 }
 
-type GetExpressCheckoutDetailsResp struct {
-	Body url.Values
+// DoExpressCheckoutPaymentResp https://developer.paypal.com/api/nvp-soap/do-express-checkout-payment-nvp/#response-fields
+type DoExpressCheckoutPaymentResp struct {
+	NVPResp
+	Token         string `url:"TOKEN"`
+	TransactionID string `url:"PAYMENTINFO_0_TRANSACTIONID"`
+	PaymentType   string `url:"PAYMENTINFO_0_PAYMENTTYPE"`
+	OrderTime     string `url:"PAYMENTINFO_0_ORDERTIME"`
+	Amount        string `url:"PAYMENTINFO_0_AMT"`
+	FeeAmount     string `url:"PAYMENTINFO_0_FEEAMT"`
+	CurrencyCode  string `url:"PAYMENTINFO_0_CURRENCYCODE"`
+	PaymentStatus string `url:"PAYMENTINFO_0_PAYMENTSTATUS"`
+	PendingReason string `url:"PAYMENTINFO_0_PENDINGREASON"`
+	ReasonCode    string `url:"PAYMENTINFO_0_REASONCODE"`
+	// BillingAgreementID is returned if the payer accepted the billing agreement of the checkout.
+	BillingAgreementID string `url:"BILLINGAGREEMENTID"`
+
+	Body url.Values `url:"-"`
 }
@@ -0,0 +1,311 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+)
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-dispute_state
+const (
+	DisputeStateRequiredAction           = "REQUIRED_ACTION"
+	DisputeStateRequiredOtherPartyAction = "REQUIRED_OTHER_PARTY_ACTION"
+	DisputeStateUnderPayPalReview        = "UNDER_PAYPAL_REVIEW"
+	DisputeStateResolved                 = disputeStateRESOLVED
+	DisputeStateOpenInquiries            = "OPEN_INQUIRIES"
+	DisputeStateAppealable               = "APPEALABLE"
+)
+
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-status
+const (
+	DisputeStatusOpen                     = "OPEN"
+	DisputeStatusWaitingForBuyerResponse  = "WAITING_FOR_BUYER_RESPONSE"
+	DisputeStatusWaitingForSellerResponse = "WAITING_FOR_SELLER_RESPONSE"
+	DisputeStatusUnderReview              = "UNDER_REVIEW"
+	DisputeStatusResolved                 = "RESOLVED"
+	DisputeStatusOther                    = "OTHER"
+)
+
+type DisputeLifeCycleStage string
+
+const (
+	DisputeLifeCycleStageInquiry        DisputeLifeCycleStage = "INQUIRY"
+	DisputeLifeCycleStageChargeback     DisputeLifeCycleStage = "CHARGEBACK"
+	DisputeLifeCycleStagePreArbitration DisputeLifeCycleStage = "PRE_ARBITRATION"
+	DisputeLifeCycleStageArbitration    DisputeLifeCycleStage = "ARBITRATION"
+)
+
+type OfferType string
+
+const (
+	OfferTypeRefund                   OfferType = "REFUND"
+	OfferTypeRefundWithReturn         OfferType = "REFUND_WITH_RETURN"
+	OfferTypeRefundWithReplacement    OfferType = "REFUND_WITH_REPLACEMENT"
+	OfferTypeReplacementWithoutRefund OfferType = "REPLACEMENT_WITHOUT_REFUND"
+)
+
+// EvidenceType lists the evidences commonly provided by sellers, see the api document for the others.
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#definition-evidence_type
+type EvidenceType string
+
+const (
+	EvidenceTypeProofOfFulfillment       EvidenceType = "PROOF_OF_FULFILLMENT"
+	EvidenceTypeProofOfRefund            EvidenceType = "PROOF_OF_REFUND"
+	EvidenceTypeProofOfDeliverySignature EvidenceType = "PROOF_OF_DELIVERY_SIGNATURE"
+	EvidenceTypeProofOfReceiptCopy       EvidenceType = "PROOF_OF_RECEIPT_COPY"
+	EvidenceTypeReturnPolicy             EvidenceType = "RETURN_POLICY"
+	EvidenceTypeBillingAgreement         EvidenceType = "BILLING_AGREEMENT"
+	EvidenceTypeProofOfReshipment        EvidenceType = "PROOF_OF_RESHIPMENT"
+	EvidenceTypeItemDescription          EvidenceType = "ITEM_DESCRIPTION"
+	EvidenceTypeProofOfReturn            EvidenceType = "PROOF_OF_RETURN"
+	EvidenceTypeProofOfShipmentPostage   EvidenceType = "PROOF_OF_SHIPMENT_POSTAGE"
+	EvidenceTypeOther                    EvidenceType = "OTHER"
+)
+
+type AcknowledgementType string
+
+const (
+	AcknowledgementTypeItemReceived            AcknowledgementType = "ITEM_RECEIVED"
+	AcknowledgementTypeItemNotReceived         AcknowledgementType = "ITEM_NOT_RECEIVED"
+	AcknowledgementTypeDamaged                 AcknowledgementType = "DAMAGED"
+	AcknowledgementTypeEmptyPackageOrDifferent AcknowledgementType = "EMPTY_PACKAGE_OR_DIFFERENT"
+	AcknowledgementTypeMissingItems            AcknowledgementType = "MISSING_ITEMS"
+)
+
+type DisputeDocument struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type DisputeMessage struct {
+	PostedBy   string            `json:"posted_by"`
+	TimePosted time.Time         `json:"time_posted"`
+	Content    string            `json:"content"`
+	Documents  []DisputeDocument `json:"documents"`
+}
+
+type ServiceDetails struct {
+	Description    string   `json:"description"`
+	ServiceStarted string   `json:"service_started"`
+	Note           string   `json:"note"`
+	SubReasons     []string `json:"sub_reasons"`
+	PurchaseURL    string   `json:"purchase_url"`
+}
+
+type MerchandizeDisputeProperties struct {
+	IssueType      string          `json:"issue_type"`
+	ServiceDetails *ServiceDetails `json:"service_details"`
+}
+
+type DisputeExtensions struct {
+	MerchantContacted            bool                          `json:"merchant_contacted"`
+	MerchantContactedOutcome     string                        `json:"merchant_contacted_outcome"`
+	MerchantContactedTime        time.Time                     `json:"merchant_contacted_time"`
+	MerchantContactedMode        string                        `json:"merchant_contacted_mode"`
+	BuyerContactedTime           time.Time                     `json:"buyer_contacted_time"`
+	BuyerContactedChannel        string                        `json:"buyer_contacted_channel"`
+	MerchandizeDisputeProperties *MerchandizeDisputeProperties `json:"merchandize_dispute_properties"`
+}
+
+type OfferHistory struct {
+	OfferTime time.Time `json:"offer_time"`
+	Actor     string    `json:"actor"`
+	EventType string    `json:"event_type"`
+	OfferType OfferType `json:"offer_type"`
+}
+
+type DisputeOffer struct {
+	BuyerRequestedAmount *Money         `json:"buyer_requested_amount"`
+	SellerOfferedAmount  *Money         `json:"seller_offered_amount"`
+	OfferType            OfferType      `json:"offer_type"`
+	History              []OfferHistory `json:"history"`
+}
+
+type TrackingInfo struct {
+	CarrierName      string `json:"carrier_name"`
+	CarrierNameOther string `json:"carrier_name_other,omitempty"`
+	TrackingURL      string `json:"tracking_url,omitempty"`
+	TrackingNumber   string `json:"tracking_number"`
+}
+
+type EvidenceInfo struct {
+	TrackingInfo []TrackingInfo `json:"tracking_info,omitempty"`
+	RefundIDs    []string       `json:"refund_ids,omitempty"`
+}
+
+type DisputeEvidence struct {
+	EvidenceType EvidenceType      `json:"evidence_type"`
+	EvidenceInfo *EvidenceInfo     `json:"evidence_info,omitempty"`
+	Documents    []DisputeDocument `json:"documents,omitempty"`
+	Notes        string            `json:"notes,omitempty"`
+	ItemID       string            `json:"item_id,omitempty"`
+}
+
+type AcceptClaimReq struct {
+	Note string `json:"note"`
+	// AcceptClaimReason is one of DID_NOT_SHIP_ITEM, TOO_TIME_CONSUMING, LOST_IN_MAIL, NOT_ABLE_TO_WIN, COMPANY_POLICY, REASON_NOT_SET.
+	AcceptClaimReason     string   `json:"accept_claim_reason,omitempty"`
+	InvoiceID             string   `json:"invoice_id,omitempty"`
+	ReturnShippingAddress *Address `json:"return_shipping_address,omitempty"`
+	// RefundAmount is the full disputed amount if nil.
+	RefundAmount *Money `json:"refund_amount,omitempty"`
+}
+
+type MakeOfferReq struct {
+	Note                  string    `json:"note"`
+	OfferType             OfferType `json:"offer_type"`
+	OfferAmount           *Money    `json:"offer_amount,omitempty"`
+	ReturnShippingAddress *Address  `json:"return_shipping_address,omitempty"`
+	InvoiceID             string    `json:"invoice_id,omitempty"`
+}
+
+// EvidenceFile is a document uploaded with the evidences, PayPal accepts JPG, GIF, PNG and PDF files up to 10MB.
+type EvidenceFile struct {
+	Name string
+	// ContentType is detected from Content if empty.
+	ContentType string
+	Content     []byte
+}
+
+type ProvideEvidenceReq struct {
+	Evidences []DisputeEvidence `json:"evidences"`
+	Files     []EvidenceFile    `json:"-"`
+}
+
+type AcknowledgeReturnItemReq struct {
+	Note                string              `json:"note,omitempty"`
+	AcknowledgementType AcknowledgementType `json:"acknowledgement_type,omitempty"`
+}
+
+type sendDisputeMessageReq struct {
+	Message string `json:"message"`
+}
+
+type disputeNoteReq struct {
+	Note string `json:"note"`
+}
+
+// multipartBody is a multipart form with a json part named input and the files,
+// it is encoded once so the request can be built again with the same body.
+type multipartBody struct {
+	contentType string
+	body        []byte
+}
+
+func newMultipartBody(input interface{}, files []EvidenceFile) (multipartBody, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="input"; filename="input.json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return multipartBody{}, err
+	}
+	if err = json.NewEncoder(part).Encode(input); err != nil {
+		return multipartBody{}, err
+	}
+
+	for i, file := range files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = http.DetectContentType(file.Content)
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file%d"; filename=%q`, i+1, file.Name))
+		header.Set("Content-Type", contentType)
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return multipartBody{}, err
+		}
+		if _, err = part.Write(file.Content); err != nil {
+			return multipartBody{}, err
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		return multipartBody{}, err
+	}
+
+	return multipartBody{contentType: w.FormDataContentType(), body: buf.Bytes()}, nil
+}
+
+func (b multipartBody) ContentType() string {
+	return b.contentType
+}
+
+func (b multipartBody) Body() (io.Reader, error) {
+	return bytes.NewReader(b.body), nil
+}
+
+// FilterDisputes lists one page of the disputes in any state matching the filters of req,
+// the next page is requested with the next_page_token of the next link.
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_list
+func (c *Client) FilterDisputes(ctx context.Context, req ListDisputesReq) (resp ListDisputesResp, err error) {
+	err = c.do(ctx, "FilterDisputes", request{method: http.MethodGet, path: listDisputesPath, query: req}, &resp)
+	return resp, err
+}
+
+// AcceptClaim accepts the liability of the dispute, the buyer is refunded and the dispute is closed.
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_accept-claim
+func (c *Client) AcceptClaim(ctx context.Context, disputeID string, req AcceptClaimReq) error {
+	return c.disputeAction(ctx, "AcceptClaim", disputeID, "/accept-claim", request{body: req})
+}
+
+// MakeOffer offers the buyer a refund, a replacement or both to resolve the dispute.
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_make-offer
+func (c *Client) MakeOffer(ctx context.Context, disputeID string, req MakeOfferReq) error {
+	return c.disputeAction(ctx, "MakeOffer", disputeID, "/make-offer", request{body: req})
+}
+
+// ProvideEvidence uploads the evidences and their documents in a multipart request,
+// the documents are referenced by name in DisputeEvidence.Documents.
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_provide-evidence
+func (c *Client) ProvideEvidence(ctx context.Context, disputeID string, req ProvideEvidenceReq) error {
+	if len(req.Evidences) == 0 {
+		return errors.New("evidences is empty")
+	}
+
+	body, err := newMultipartBody(req, req.Files)
+	if err != nil {
+		return err
+	}
+
+	return c.disputeAction(ctx, "ProvideEvidence", disputeID, "/provide-evidence", request{upload: body})
+}
+
+// SendDisputeMessage sends a message to the buyer about the dispute.
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_send-message
+func (c *Client) SendDisputeMessage(ctx context.Context, disputeID string, message string) error {
+	return c.disputeAction(ctx, "SendDisputeMessage", disputeID, "/send-message", request{body: sendDisputeMessageReq{Message: message}})
+}
+
+// EscalateDispute escalates the dispute to a PayPal claim.
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_escalate
+func (c *Client) EscalateDispute(ctx context.Context, disputeID string, note string) error {
+	return c.disputeAction(ctx, "EscalateDispute", disputeID, "/escalate", request{body: disputeNoteReq{Note: note}})
+}
+
+// AcknowledgeReturnItem acknowledges the item returned by the buyer.
+// https://developer.paypal.com/docs/api/customer-disputes/v1/#disputes_acknowledge-return-item
+func (c *Client) AcknowledgeReturnItem(ctx context.Context, disputeID string, req AcknowledgeReturnItemReq) error {
+	return c.disputeAction(ctx, "AcknowledgeReturnItem", disputeID, "/acknowledge-return-item", request{body: req})
+}
+
+func (c *Client) disputeAction(ctx context.Context, name, disputeID, action string, r request) error {
+	if disputeID == "" {
+		return errors.New("disputeID is zero")
+	}
+
+	r.method = http.MethodPost
+	r.path = showDisputeDetailsPath + disputeID + action
+	return c.do(ctx, name, r, nil)
+}
@@ -0,0 +1,161 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"testing"
+)
+
+func TestClient_FilterDisputes(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"GET " + listDisputesPath: {body: map[string]interface{}{
+			"items": []map[string]interface{}{{"dispute_id": "PP-D-27803", "status": "WAITING_FOR_SELLER_RESPONSE", "dispute_state": "REQUIRED_ACTION"}},
+		}},
+		"GET " + showDisputeDetailsPath + "PP-D-27803": {body: map[string]interface{}{
+			"dispute_id":               "PP-D-27803",
+			"dispute_life_cycle_stage": "CHARGEBACK",
+			"messages":                 []map[string]interface{}{{"posted_by": "BUYER", "time_posted": "2021-12-01T08:00:00Z", "content": "Item not received"}},
+			"offer":                    map[string]interface{}{"buyer_requested_amount": map[string]string{"currency_code": "USD", "value": "10.00"}},
+			"extensions": map[string]interface{}{
+				"merchandize_dispute_properties": map[string]interface{}{"issue_type": "SERVICE", "service_details": map[string]interface{}{"sub_reasons": []string{"INCOMPLETE"}}},
+			},
+		}},
+	})
+	ctx := context.Background()
+
+	disputes, err := c.FilterDisputes(ctx, ListDisputesReq{DisputeState: DisputeStateRequiredAction + "," + DisputeStateUnderPayPalReview})
+	if err != nil || len(disputes.Items) != 1 || disputes.Items[0].Status != DisputeStatusWaitingForSellerResponse {
+		t.Fatalf("FilterDisputes() got = %+v, err = %v", disputes, err)
+	}
+	if got := mock.requests[0].URL.RawQuery; got != "dispute_state=REQUIRED_ACTION%2CUNDER_PAYPAL_REVIEW" {
+		t.Errorf("FilterDisputes() query = %v", got)
+	}
+
+	detail, err := c.ShowDisputeDetails(ctx, "PP-D-27803")
+	if err != nil || detail.DisputeLifeCycleStage != DisputeLifeCycleStageChargeback || len(detail.Messages) != 1 ||
+		detail.Messages[0].TimePosted.IsZero() || detail.Offer == nil || detail.Offer.BuyerRequestedAmount.Value != "10.00" ||
+		detail.Extensions.MerchandizeDisputeProperties.ServiceDetails.SubReasons[0] != "INCOMPLETE" {
+		t.Errorf("ShowDisputeDetails() got = %+v, err = %v", detail, err)
+	}
+}
+
+func TestClient_DisputeActions(t *testing.T) {
+	path := showDisputeDetailsPath + "PP-D-27803"
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + path + "/accept-claim":            {body: map[string]interface{}{"links": []interface{}{}}},
+		"POST " + path + "/make-offer":              {body: map[string]interface{}{"links": []interface{}{}}},
+		"POST " + path + "/send-message":            {body: map[string]interface{}{"links": []interface{}{}}},
+		"POST " + path + "/escalate":                {body: map[string]interface{}{"links": []interface{}{}}},
+		"POST " + path + "/acknowledge-return-item": {body: map[string]interface{}{"links": []interface{}{}}},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		fn   func() error
+		want string
+	}{
+		{
+			name: "AcceptClaim",
+			fn: func() error {
+				return c.AcceptClaim(ctx, "PP-D-27803", AcceptClaimReq{Note: "Full refund", RefundAmount: &Money{CurrencyCode: "USD", Value: "10.00"}})
+			},
+			want: `{"note":"Full refund","refund_amount":{"currency_code":"USD","value":"10.00"}}`,
+		},
+		{
+			name: "MakeOffer",
+			fn: func() error {
+				return c.MakeOffer(ctx, "PP-D-27803", MakeOfferReq{Note: "Partial refund", OfferType: OfferTypeRefund, OfferAmount: &Money{CurrencyCode: "USD", Value: "5.00"}})
+			},
+			want: `{"note":"Partial refund","offer_type":"REFUND","offer_amount":{"currency_code":"USD","value":"5.00"}}`,
+		},
+		{
+			name: "SendDisputeMessage",
+			fn:   func() error { return c.SendDisputeMessage(ctx, "PP-D-27803", "Shipped yesterday") },
+			want: `{"message":"Shipped yesterday"}`,
+		},
+		{
+			name: "EscalateDispute",
+			fn:   func() error { return c.EscalateDispute(ctx, "PP-D-27803", "Escalating to claim") },
+			want: `{"note":"Escalating to claim"}`,
+		},
+		{
+			name: "AcknowledgeReturnItem",
+			fn: func() error {
+				return c.AcknowledgeReturnItem(ctx, "PP-D-27803", AcknowledgeReturnItemReq{AcknowledgementType: AcknowledgementTypeItemReceived})
+			},
+			want: `{"acknowledgement_type":"ITEM_RECEIVED"}`,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(mock.bodies[i]); got != tt.want {
+				t.Errorf("%s() body = %s, want %s", tt.name, got, tt.want)
+			}
+		})
+	}
+
+	if err := c.EscalateDispute(ctx, "PP-D-UNKNOWN", ""); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("EscalateDispute() err = %v, want %v", err, ErrResourceNotFound)
+	}
+	if err := c.AcceptClaim(ctx, "", AcceptClaimReq{}); err == nil {
+		t.Errorf("AcceptClaim() err = nil, want disputeID is zero")
+	}
+}
+
+func TestClient_ProvideEvidence(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + showDisputeDetailsPath + "PP-D-27803/provide-evidence": {body: map[string]interface{}{"links": []interface{}{}}},
+	})
+	pdf := []byte("%PDF-1.4 tracking receipt")
+
+	err := c.ProvideEvidence(context.Background(), "PP-D-27803", ProvideEvidenceReq{
+		Evidences: []DisputeEvidence{{
+			EvidenceType: EvidenceTypeProofOfFulfillment,
+			EvidenceInfo: &EvidenceInfo{TrackingInfo: []TrackingInfo{{CarrierName: "FEDEX", TrackingNumber: "122533485"}}},
+			Documents:    []DisputeDocument{{Name: "receipt.pdf"}},
+		}},
+		Files: []EvidenceFile{{Name: "receipt.pdf", Content: pdf}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(mock.requests[0].Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("ProvideEvidence() Content-Type = %v, err = %v", mediaType, err)
+	}
+	r := multipart.NewReader(bytes.NewReader(mock.bodies[0]), params["boundary"])
+
+	part, err := r.NextPart()
+	if err != nil || part.FormName() != "input" || part.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("ProvideEvidence() input part = %v, err = %v", part, err)
+	}
+	var input ProvideEvidenceReq
+	if err = json.NewDecoder(part).Decode(&input); err != nil || input.Evidences[0].EvidenceInfo.TrackingInfo[0].TrackingNumber != "122533485" {
+		t.Errorf("ProvideEvidence() input = %+v, err = %v", input, err)
+	}
+
+	part, err = r.NextPart()
+	if err != nil || part.FileName() != "receipt.pdf" || part.Header.Get("Content-Type") != "application/pdf" {
+		t.Fatalf("ProvideEvidence() file part = %v, err = %v", part, err)
+	}
+	if content, _ := ioutil.ReadAll(part); !bytes.Equal(content, pdf) {
+		t.Errorf("ProvideEvidence() file = %s", content)
+	}
+
+	if err = c.ProvideEvidence(context.Background(), "PP-D-27803", ProvideEvidenceReq{}); err == nil {
+		t.Errorf("ProvideEvidence() err = nil, want evidences is empty")
+	}
+	if len(mock.requests) != 1 {
+		t.Errorf("ProvideEvidence() sent %d requests, want 1", len(mock.requests))
+	}
+}
@@ -1,20 +1,346 @@
 package paypal
 
 import (
-	"fmt"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
-type ErrorResp map[string]interface{}
+var (
+	// ErrResourceNotFound returns when the order, capture, authorization or refund does not exist.
+	ErrResourceNotFound = errors.New("paypal resource not found")
+	// ErrPermissionDenied returns when the app has no permission on the resource.
+	ErrPermissionDenied = errors.New("paypal permission denied")
 
-// Err error caused by http request and response
-func (e ErrorResp) Err(err error) error {
-	if err != nil {
-		return err
+	// ErrOrderNotApproved returns when capturing or authorizing an order the payer has not approved.
+	ErrOrderNotApproved = errors.New("paypal order not approved")
+	// ErrInstrumentDeclined returns when the funding source of the payer is declined.
+	ErrInstrumentDeclined = errors.New("paypal instrument declined")
+	// ErrDuplicateInvoiceID returns when the invoice id was used by a completed transaction.
+	ErrDuplicateInvoiceID = errors.New("paypal duplicate invoice id")
+
+	// ErrCaptureFullyRefunded returns when refunding a capture that is refunded already.
+	ErrCaptureFullyRefunded = errors.New("paypal capture fully refunded")
+	// ErrRefundAmountExceeded returns when the refund amount exceeds the remaining amount of the capture.
+	ErrRefundAmountExceeded = errors.New("paypal refund amount exceeded")
+	// ErrRefundTimeLimitExceeded returns when the capture is too old to be refunded.
+	ErrRefundTimeLimitExceeded = errors.New("paypal refund time limit exceeded")
+	// ErrRefundNotAllowed returns when the capture can not be refunded, e.g. it is pending or disputed.
+	ErrRefundNotAllowed = errors.New("paypal refund not allowed")
+
+	// ErrAuthorizationExpired returns when the authorization is expired, reauthorize or create a new order.
+	ErrAuthorizationExpired = errors.New("paypal authorization expired")
+	// ErrAuthorizationVoided returns when the authorization is voided.
+	ErrAuthorizationVoided = errors.New("paypal authorization voided")
+	// ErrAuthorizationCaptured returns when the authorization is captured already.
+	ErrAuthorizationCaptured = errors.New("paypal authorization captured")
+	// ErrMaxCaptureExceeded returns when the capture amount or count exceeds the authorization.
+	ErrMaxCaptureExceeded = errors.New("paypal max capture exceeded")
+
+	// ErrWebhookURLExists returns when creating a webhook for a url that is subscribed already.
+	ErrWebhookURLExists = errors.New("paypal webhook url already exists")
+	// ErrWebhookLimitExceeded returns when the app has too many webhooks.
+	ErrWebhookLimitExceeded = errors.New("paypal webhook number limit exceeded")
+
+	// ErrSubscriptionStatusInvalid returns when the action is not allowed in the status of the subscription,
+	// e.g. activating a cancelled subscription.
+	ErrSubscriptionStatusInvalid = errors.New("paypal subscription status invalid")
+	// ErrPlanStatusInvalid returns when subscribing to a plan that is not active.
+	ErrPlanStatusInvalid = errors.New("paypal plan status invalid")
+
+	// ErrCheckoutTokenExpired returns when the express checkout token is expired or the checkout is completed already.
+	ErrCheckoutTokenExpired = errors.New("paypal checkout token expired")
+	// ErrBillingAgreementCanceled returns when charging or updating a billing agreement that is canceled.
+	ErrBillingAgreementCanceled = errors.New("paypal billing agreement canceled")
+)
+
+// errorKinds maps the error name or details issue to the sentinel errors.
+// https://developer.paypal.com/api/rest/reference/orders/v2/errors/
+// https://developer.paypal.com/docs/api/payments/v2/#errors
+// https://developer.paypal.com/docs/api/subscriptions/v1/#errors
+var errorKinds = map[string]error{
+	"RESOURCE_NOT_FOUND":  ErrResourceNotFound,
+	"INVALID_RESOURCE_ID": ErrResourceNotFound,
+	"NOT_AUTHORIZED":      ErrPermissionDenied,
+	"PERMISSION_DENIED":   ErrPermissionDenied,
+
+	"ORDER_NOT_APPROVED":   ErrOrderNotApproved,
+	"INSTRUMENT_DECLINED":  ErrInstrumentDeclined,
+	"TRANSACTION_REFUSED":  ErrInstrumentDeclined,
+	"DUPLICATE_INVOICE_ID": ErrDuplicateInvoiceID,
+
+	"CAPTURE_FULLY_REFUNDED":                 ErrCaptureFullyRefunded,
+	"REFUND_AMOUNT_EXCEEDED":                 ErrRefundAmountExceeded,
+	"REFUND_TIME_LIMIT_EXCEEDED":             ErrRefundTimeLimitExceeded,
+	"REFUND_NOT_ALLOWED":                     ErrRefundNotAllowed,
+	"REFUND_NOT_PERMITTED_DUE_TO_CHARGEBACK": ErrRefundNotAllowed,
+	"PENDING_CAPTURE":                        ErrRefundNotAllowed,
+
+	"AUTHORIZATION_EXPIRED":          ErrAuthorizationExpired,
+	"AUTHORIZATION_VOIDED":           ErrAuthorizationVoided,
+	"PREVIOUSLY_VOIDED":              ErrAuthorizationVoided,
+	"AUTHORIZATION_ALREADY_CAPTURED": ErrAuthorizationCaptured,
+	"PREVIOUSLY_CAPTURED":            ErrAuthorizationCaptured,
+	"MAX_CAPTURE_AMOUNT_EXCEEDED":    ErrMaxCaptureExceeded,
+	"MAX_CAPTURE_COUNT_EXCEEDED":     ErrMaxCaptureExceeded,
+
+	"WEBHOOK_URL_ALREADY_EXISTS":    ErrWebhookURLExists,
+	"WEBHOOK_NUMBER_LIMIT_EXCEEDED": ErrWebhookLimitExceeded,
+
+	"SUBSCRIPTION_STATUS_INVALID": ErrSubscriptionStatusInvalid,
+	"PLAN_STATUS_INVALID":         ErrPlanStatusInvalid,
+}
+
+// nvpErrorKinds maps the L_ERRORCODEn of NVP responses to the sentinel errors.
+// https://developer.paypal.com/api/nvp-soap/errors/
+var nvpErrorKinds = map[string]error{
+	"10002": ErrPermissionDenied,
+	"10007": ErrPermissionDenied,
+	"10011": ErrResourceNotFound,
+
+	"10009": ErrRefundNotAllowed,
+
+	"10201": ErrBillingAgreementCanceled,
+
+	"10411": ErrCheckoutTokenExpired,
+	"10412": ErrDuplicateInvoiceID,
+	"10417": ErrInstrumentDeclined,
+	"10486": ErrInstrumentDeclined,
+}
+
+// nvpRetryableCodes are internal and processing errors of NVP that may succeed if retried later.
+var nvpRetryableCodes = map[string]bool{
+	"10001": true,
+	"10445": true,
+}
+
+// ErrorDetail https://developer.paypal.com/api/rest/responses/#link-errordetails
+type ErrorDetail struct {
+	Field       string `json:"field,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Issue       string `json:"issue"`
+	Description string `json:"description,omitempty"`
+}
+
+// APIError is a failed response of paypal, errors.Is matches the sentinel error of its name or details issue.
+// https://developer.paypal.com/api/rest/responses/#link-failedrequests
+type APIError struct {
+	StatusCode int           `json:"-"`
+	Name       string        `json:"name"`
+	Message    string        `json:"message"`
+	DebugID    string        `json:"debug_id"`
+	Details    []ErrorDetail `json:"details,omitempty"`
+	Links      []HATEOASLink `json:"links,omitempty"`
+
+	// OAuth2 errors of the token endpoint carry error and error_description instead.
+	OAuthError       string `json:"error,omitempty"`
+	OAuthDescription string `json:"error_description,omitempty"`
+}
+
+// ErrorResp is the failed response of paypal.
+//
+// Deprecated: use APIError.
+type ErrorResp = APIError
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString("paypal: ")
+	b.WriteString(strconv.Itoa(e.StatusCode))
+	if name := e.name(); name != "" {
+		b.WriteString(" " + name)
+	}
+	if message := e.message(); message != "" {
+		b.WriteString(": " + message)
+	}
+	for _, detail := range e.Details {
+		b.WriteString(" [" + detail.Issue)
+		if detail.Field != "" {
+			b.WriteString(" " + detail.Field)
+		}
+		if detail.Description != "" {
+			b.WriteString(": " + detail.Description)
+		}
+		b.WriteString("]")
+	}
+	if e.DebugID != "" {
+		b.WriteString(" (debug_id " + e.DebugID + ")")
 	}
 
-	if e != nil {
-		return fmt.Errorf("%v", e)
+	return b.String()
+}
+
+// Is matches the sentinel error of any details issue or of the error name.
+func (e *APIError) Is(target error) bool {
+	if target == nil {
+		return false
+	}
+	for _, detail := range e.Details {
+		if errorKinds[detail.Issue] == target {
+			return true
+		}
 	}
 
-	return nil
+	return errorKinds[e.Name] == target
+}
+
+// Issue returns the issue of the first details, or an empty string.
+func (e *APIError) Issue() string {
+	if len(e.Details) == 0 {
+		return ""
+	}
+	return e.Details[0].Issue
+}
+
+func (e *APIError) name() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return e.OAuthError
+}
+
+func (e *APIError) message() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.OAuthDescription
+}
+
+// NVPErrorItem is one of the L_ERRORCODEn, L_SHORTMESSAGEn, L_LONGMESSAGEn and L_SEVERITYCODEn of NVP responses.
+type NVPErrorItem struct {
+	Code         string
+	ShortMessage string
+	LongMessage  string
+	SeverityCode string
+}
+
+// NVPError is a NVP response whose ACK is Failure or FailureWithWarning,
+// errors.Is matches the sentinel errors of all its error codes.
+// https://developer.paypal.com/api/nvp-soap/NVPAPIOverview/#error-responses
+type NVPError struct {
+	Ack           string
+	CorrelationID string
+	Errors        []NVPErrorItem
+}
+
+func (e *NVPError) Error() string {
+	var b strings.Builder
+	b.WriteString("paypal nvp: " + e.Ack)
+	for _, item := range e.Errors {
+		b.WriteString(" [" + item.Code)
+		if item.LongMessage != "" {
+			b.WriteString(": " + item.LongMessage)
+		} else if item.ShortMessage != "" {
+			b.WriteString(": " + item.ShortMessage)
+		}
+		b.WriteString("]")
+	}
+	if e.CorrelationID != "" {
+		b.WriteString(" (correlation_id " + e.CorrelationID + ")")
+	}
+
+	return b.String()
+}
+
+// Is matches the sentinel error of any of the error codes.
+func (e *NVPError) Is(target error) bool {
+	for _, item := range e.Errors {
+		if kind, ok := nvpErrorKinds[item.Code]; ok && kind == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Code returns the code of the first error, or an empty string.
+func (e *NVPError) Code() string {
+	if len(e.Errors) == 0 {
+		return ""
+	}
+	return e.Errors[0].Code
+}
+
+// parseNVPError returns *NVPError if the ACK of values is not Success or SuccessWithWarning.
+func parseNVPError(values url.Values) error {
+	ack := values.Get("ACK")
+	if ack == AckSuccess || ack == AckSuccessWithWarning {
+		return nil
+	}
+
+	nvpErr := &NVPError{Ack: ack, CorrelationID: values.Get("CORRELATIONID")}
+	for i := 0; ; i++ {
+		n := strconv.Itoa(i)
+		code := values.Get("L_ERRORCODE" + n)
+		if code == "" {
+			break
+		}
+		nvpErr.Errors = append(nvpErr.Errors, NVPErrorItem{
+			Code:         code,
+			ShortMessage: values.Get("L_SHORTMESSAGE" + n),
+			LongMessage:  values.Get("L_LONGMESSAGE" + n),
+			SeverityCode: values.Get("L_SEVERITYCODE" + n),
+		})
+	}
+
+	return nvpErr
+}
+
+// IsRetryable reports whether the request may succeed if retried later: rate limited, 5xx or network timeout.
+// Retry POST requests with the same PayPal-Request-Id to not create the resource twice.
+func IsRetryable(err error) bool {
+	if IsRateLimited(err) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var nvpErr *NVPError
+	if errors.As(err, &nvpErr) {
+		return nvpRetryableCodes[nvpErr.Code()]
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsAuthError reports whether the credentials or token are rejected, or the app lacks the permission.
+func IsAuthError(err error) bool {
+	var nvpErr *NVPError
+	if errors.As(err, &nvpErr) {
+		return errors.Is(nvpErr, ErrPermissionDenied)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden ||
+		apiErr.OAuthError != "" || errors.Is(apiErr, ErrPermissionDenied)
+}
+
+// IsRateLimited reports whether paypal throttles the requests.
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Name == "RATE_LIMIT_REACHED"
+}
+
+// newAPIError completes the error decoded from a failed response.
+func newAPIError(resp *http.Response, apiErr APIError) *APIError {
+	apiErr.StatusCode = resp.StatusCode
+	if apiErr.DebugID == "" {
+		apiErr.DebugID = resp.Header.Get(headerDebugID)
+	}
+	return &apiErr
 }
@@ -0,0 +1,131 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *APIError
+		wantErrs []error
+	}{
+		{name: "unknown", err: &APIError{StatusCode: http.StatusInternalServerError, Name: "INTERNAL_SERVER_ERROR"}},
+		{name: "by name", err: &APIError{StatusCode: http.StatusNotFound, Name: "RESOURCE_NOT_FOUND"}, wantErrs: []error{ErrResourceNotFound}},
+		{
+			name:     "by issue",
+			err:      &APIError{StatusCode: http.StatusUnprocessableEntity, Name: "UNPROCESSABLE_ENTITY", Details: []ErrorDetail{{Issue: "ORDER_NOT_APPROVED"}}},
+			wantErrs: []error{ErrOrderNotApproved},
+		},
+		{
+			name: "by name and issues",
+			err: &APIError{StatusCode: http.StatusNotFound, Name: "RESOURCE_NOT_FOUND",
+				Details: []ErrorDetail{{Issue: "INVALID_PARAMETER_VALUE"}, {Issue: "PREVIOUSLY_VOIDED"}, {Issue: "REFUND_NOT_ALLOWED"}}},
+			wantErrs: []error{ErrResourceNotFound, ErrAuthorizationVoided, ErrRefundNotAllowed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", tt.err)
+			for _, kind := range errorKinds {
+				want := false
+				for _, wantErr := range tt.wantErrs {
+					want = want || kind == wantErr
+				}
+				if got := errors.Is(err, kind); got != want {
+					t.Errorf("errors.Is(%v, %v) = %v", err, kind, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNVPError_Is(t *testing.T) {
+	err := parseNVPError(url.Values{
+		"ACK":          {AckFailure},
+		"L_ERRORCODE0": {"10002"}, "L_SHORTMESSAGE0": {"Security error"},
+		"L_ERRORCODE1": {"10412"}, "L_SHORTMESSAGE1": {"Duplicate invoice"},
+	})
+	err = fmt.Errorf("wrapped: %w", err)
+	for _, kind := range nvpErrorKinds {
+		want := kind == ErrPermissionDenied || kind == ErrDuplicateInvoiceID
+		if got := errors.Is(err, kind); got != want {
+			t.Errorf("errors.Is(%v, %v) = %v", err, kind, got)
+		}
+	}
+}
+
+func TestAPIError_Classify(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		wantRetryable   bool
+		wantAuth        bool
+		wantRateLimited bool
+	}{
+		{name: "server error", err: &APIError{StatusCode: http.StatusServiceUnavailable}, wantRetryable: true},
+		{name: "rate limited", err: &APIError{StatusCode: http.StatusTooManyRequests, Name: "RATE_LIMIT_REACHED"}, wantRetryable: true, wantRateLimited: true},
+		{name: "invalid token", err: &APIError{StatusCode: http.StatusUnauthorized, Name: "AUTHENTICATION_FAILURE"}, wantAuth: true},
+		{name: "invalid client", err: &APIError{StatusCode: http.StatusUnauthorized, OAuthError: "invalid_client"}, wantAuth: true},
+		{name: "not authorized", err: &APIError{StatusCode: http.StatusForbidden, Name: "NOT_AUTHORIZED"}, wantAuth: true},
+		{name: "unprocessable", err: &APIError{StatusCode: http.StatusUnprocessableEntity, Name: "UNPROCESSABLE_ENTITY"}},
+		{name: "timeout", err: fmt.Errorf("post: %w", timeoutError{}), wantRetryable: true},
+		{name: "other", err: errors.New("orderID is zero")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.wantRetryable {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.wantRetryable)
+			}
+			if got := IsAuthError(tt.err); got != tt.wantAuth {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.wantAuth)
+			}
+			if got := IsRateLimited(tt.err); got != tt.wantRateLimited {
+				t.Errorf("IsRateLimited() = %v, want %v", got, tt.wantRateLimited)
+			}
+		})
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	c, _ := newMockClient(t, map[string]mockResponse{
+		"POST " + ordersPath + "/5O190127TN364715T/capture": {status: http.StatusUnprocessableEntity, body: map[string]interface{}{
+			"name":     "UNPROCESSABLE_ENTITY",
+			"message":  "The requested action could not be performed, semantically incorrect, or failed business validation.",
+			"debug_id": "90957fca61718",
+			"details":  []map[string]string{{"issue": "ORDER_NOT_APPROVED", "description": "Payer has not yet approved the Order for payment."}},
+			"links":    []map[string]string{{"href": "https://developer.paypal.com/docs/api/orders/v2/#error-ORDER_NOT_APPROVED", "rel": "information_link"}},
+		}},
+	})
+
+	_, err := c.CaptureOrder(context.Background(), "5O190127TN364715T", "")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("CaptureOrder() err = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.DebugID != "90957fca61718" || apiErr.Issue() != "ORDER_NOT_APPROVED" || len(apiErr.Links) != 1 {
+		t.Errorf("CaptureOrder() err = %+v", apiErr)
+	}
+	if !errors.Is(err, ErrOrderNotApproved) {
+		t.Errorf("CaptureOrder() err = %v, want %v", err, ErrOrderNotApproved)
+	}
+	want := "paypal: 422 UNPROCESSABLE_ENTITY: The requested action could not be performed, semantically incorrect, or failed business validation." +
+		" [ORDER_NOT_APPROVED: Payer has not yet approved the Order for payment.] (debug_id 90957fca61718)"
+	if err.Error() != want {
+		t.Errorf("CaptureOrder() err = %v, want %v", err, want)
+	}
+}
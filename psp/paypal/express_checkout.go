@@ -0,0 +1,265 @@
+package paypal
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PAYMENTREQUEST_n_PAYMENTACTION
+const (
+	PaymentActionSale          = "Sale"
+	PaymentActionAuthorization = "Authorization"
+	PaymentActionOrder         = "Order"
+)
+
+// L_PAYMENTREQUEST_n_ITEMCATEGORYm
+const (
+	ItemCategoryDigital  = "Digital"
+	ItemCategoryPhysical = "Physical"
+)
+
+// L_BILLINGTYPEn
+const (
+	BillingTypeMerchantInitiatedBilling                = "MerchantInitiatedBilling"
+	BillingTypeMerchantInitiatedBillingSingleAgreement = "MerchantInitiatedBillingSingleAgreement"
+	BillingTypeRecurringPayments                       = "RecurringPayments"
+)
+
+// LANDINGPAGE and SOLUTIONTYPE
+const (
+	LandingPageBilling = "Billing"
+	LandingPageLogin   = "Login"
+
+	SolutionTypeSole = "Sole"
+	SolutionTypeMark = "Mark"
+)
+
+const (
+	paymentRequestPrefix     = "PAYMENTREQUEST_"
+	paymentRequestItemPrefix = "L_PAYMENTREQUEST_"
+	nvpListPrefix            = "L_"
+)
+
+// PaymentRequest is the PAYMENTREQUEST_n group of express checkout, the amounts are formatted like 10.99.
+// https://developer.paypal.com/api/nvp-soap/set-express-checkout-nvp/#payment-details-type-fields
+type PaymentRequest struct {
+	Amount           string `url:"AMT"`
+	CurrencyCode     string `url:"CURRENCYCODE,omitempty"`
+	ItemAmount       string `url:"ITEMAMT,omitempty"`
+	ShippingAmount   string `url:"SHIPPINGAMT,omitempty"`
+	InsuranceAmount  string `url:"INSURANCEAMT,omitempty"`
+	ShippingDiscount string `url:"SHIPDISCAMT,omitempty"`
+	HandlingAmount   string `url:"HANDLINGAMT,omitempty"`
+	TaxAmount        string `url:"TAXAMT,omitempty"`
+	Desc             string `url:"DESC,omitempty"`
+	Custom           string `url:"CUSTOM,omitempty"`
+	Invoice          string `url:"INVNUM,omitempty"`
+	NotifyURL        string `url:"NOTIFYURL,omitempty"`
+	PaymentAction    string `url:"PAYMENTACTION,omitempty"`
+	// PaymentRequestID identifies the payment of a parallel payment.
+	PaymentRequestID string `url:"PAYMENTREQUESTID,omitempty"`
+	// SellerPayPalAccountID is the email or payer id of the seller receiving a parallel payment.
+	SellerPayPalAccountID string `url:"SELLERPAYPALACCOUNTID,omitempty"`
+
+	ShipToName        string `url:"SHIPTONAME,omitempty"`
+	ShipToStreet      string `url:"SHIPTOSTREET,omitempty"`
+	ShipToStreet2     string `url:"SHIPTOSTREET2,omitempty"`
+	ShipToCity        string `url:"SHIPTOCITY,omitempty"`
+	ShipToState       string `url:"SHIPTOSTATE,omitempty"`
+	ShipToZip         string `url:"SHIPTOZIP,omitempty"`
+	ShipToCountryCode string `url:"SHIPTOCOUNTRYCODE,omitempty"`
+	ShipToPhoneNum    string `url:"SHIPTOPHONENUM,omitempty"`
+
+	Items []PaymentItem `url:"-"`
+}
+
+// PaymentItem is the L_PAYMENTREQUEST_n_*m line item of a payment request.
+// https://developer.paypal.com/api/nvp-soap/set-express-checkout-nvp/#payment-details-item-type-fields
+type PaymentItem struct {
+	Name         string `url:"NAME,omitempty"`
+	Desc         string `url:"DESC,omitempty"`
+	Amount       string `url:"AMT"`
+	Number       string `url:"NUMBER,omitempty"`
+	Quantity     string `url:"QTY,omitempty"`
+	TaxAmount    string `url:"TAXAMT,omitempty"`
+	ItemCategory string `url:"ITEMCATEGORY,omitempty"`
+	ItemURL      string `url:"ITEMURL,omitempty"`
+}
+
+// BillingAgreement is the L_*n billing agreement the payer accepts during checkout,
+// e.g. for reference transactions later.
+// https://developer.paypal.com/api/nvp-soap/set-express-checkout-nvp/#billing-agreement-details-type-fields
+type BillingAgreement struct {
+	BillingType string `url:"BILLINGTYPE"`
+	Description string `url:"BILLINGAGREEMENTDESCRIPTION,omitempty"`
+	// PaymentType is Any or InstantOnly.
+	PaymentType string `url:"PAYMENTTYPE,omitempty"`
+	Custom      string `url:"BILLINGAGREEMENTCUSTOM,omitempty"`
+}
+
+// encodeIndexed encodes v into form with the keys prefix + field + suffix.
+func encodeIndexed(form url.Values, prefix, suffix string, v interface{}) error {
+	values := url.Values{}
+	if err := nvpEncoder.Encode(v, values); err != nil {
+		return err
+	}
+	for key, value := range values {
+		form[prefix+key+suffix] = value
+	}
+
+	return nil
+}
+
+// encodePaymentRequests encodes the payment requests and their items with indexed keys,
+// e.g. PAYMENTREQUEST_1_AMT and L_PAYMENTREQUEST_1_NAME0.
+// They replace all the PAYMENTREQUEST_n keys already in form, e.g. encoded from the legacy fields of the request.
+func encodePaymentRequests(form url.Values, paymentRequests []PaymentRequest) error {
+	if len(paymentRequests) == 0 {
+		return nil
+	}
+	for key := range form {
+		if strings.HasPrefix(key, paymentRequestPrefix) || strings.HasPrefix(key, paymentRequestItemPrefix) {
+			delete(form, key)
+		}
+	}
+
+	for n, paymentRequest := range paymentRequests {
+		index := strconv.Itoa(n)
+		if err := encodeIndexed(form, paymentRequestPrefix+index+"_", "", paymentRequest); err != nil {
+			return err
+		}
+		for m, item := range paymentRequest.Items {
+			if err := encodeIndexed(form, paymentRequestItemPrefix+index+"_", strconv.Itoa(m), item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func encodeBillingAgreements(form url.Values, billingAgreements []BillingAgreement) error {
+	for n, billingAgreement := range billingAgreements {
+		if err := encodeIndexed(form, nvpListPrefix, strconv.Itoa(n), billingAgreement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodePaymentRequests decodes all the PAYMENTREQUEST_n groups and their items of values.
+func decodePaymentRequests(values url.Values) ([]PaymentRequest, error) {
+	groups := make(map[int]url.Values)
+	items := make(map[int]map[int]url.Values)
+	for key, value := range values {
+		switch {
+		case strings.HasPrefix(key, paymentRequestPrefix):
+			n, field, ok := splitGroup(strings.TrimPrefix(key, paymentRequestPrefix))
+			if !ok {
+				continue
+			}
+			if groups[n] == nil {
+				groups[n] = url.Values{}
+			}
+			groups[n][field] = value
+		case strings.HasPrefix(key, paymentRequestItemPrefix):
+			n, field, ok := splitGroup(strings.TrimPrefix(key, paymentRequestItemPrefix))
+			if !ok {
+				continue
+			}
+			field, m, ok := splitIndex(field)
+			if !ok {
+				continue
+			}
+			if items[n] == nil {
+				items[n] = make(map[int]url.Values)
+			}
+			if items[n][m] == nil {
+				items[n][m] = url.Values{}
+			}
+			items[n][m][field] = value
+		}
+	}
+
+	var paymentRequests []PaymentRequest
+	for _, n := range sortedIndexes(groups) {
+		var paymentRequest PaymentRequest
+		if err := nvpDecoder.Decode(&paymentRequest, groups[n]); err != nil {
+			return nil, err
+		}
+		for _, m := range sortedIndexes(items[n]) {
+			var item PaymentItem
+			if err := nvpDecoder.Decode(&item, items[n][m]); err != nil {
+				return nil, err
+			}
+			paymentRequest.Items = append(paymentRequest.Items, item)
+		}
+		paymentRequests = append(paymentRequests, paymentRequest)
+	}
+
+	return paymentRequests, nil
+}
+
+// decodeList groups the list fields of values by index, e.g. L_AMT0 and L_NAME0 to AMT and NAME of 0.
+func decodeList(values url.Values) map[int]url.Values {
+	groups := make(map[int]url.Values)
+	for key, value := range values {
+		if !strings.HasPrefix(key, nvpListPrefix) {
+			continue
+		}
+		field, n, ok := splitIndex(strings.TrimPrefix(key, nvpListPrefix))
+		if !ok {
+			continue
+		}
+		if groups[n] == nil {
+			groups[n] = url.Values{}
+		}
+		groups[n][field] = value
+	}
+
+	return groups
+}
+
+// splitGroup splits 1_AMT to 1 and AMT.
+func splitGroup(key string) (int, string, bool) {
+	i := strings.IndexByte(key, '_')
+	if i <= 0 {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(key[:i])
+	if err != nil {
+		return 0, "", false
+	}
+
+	return n, key[i+1:], true
+}
+
+// splitIndex splits the trailing index of the key, e.g. NAME10 to NAME and 10.
+func splitIndex(key string) (string, int, bool) {
+	i := len(key)
+	for i > 0 && key[i-1] >= '0' && key[i-1] <= '9' {
+		i--
+	}
+	if i == 0 || i == len(key) {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(key[i:])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return key[:i], n, true
+}
+
+func sortedIndexes(groups map[int]url.Values) []int {
+	indexes := make([]int, 0, len(groups))
+	for n := range groups {
+		indexes = append(indexes, n)
+	}
+	sort.Ints(indexes)
+
+	return indexes
+}
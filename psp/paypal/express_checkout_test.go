@@ -0,0 +1,152 @@
+package paypal
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestEncodePaymentRequests(t *testing.T) {
+	paymentRequests := []PaymentRequest{
+		{
+			Amount: "25.00", CurrencyCode: "USD", ItemAmount: "20.00", ShippingAmount: "3.00", TaxAmount: "2.00",
+			Invoice: "INV-1", PaymentAction: PaymentActionSale, PaymentRequestID: "store-a",
+			ShipToName: "John Doe", ShipToCountryCode: "US",
+			Items: []PaymentItem{
+				{Name: "Shirt", Amount: "5.00", Quantity: "2", ItemCategory: ItemCategoryPhysical},
+				{Name: "Hat", Amount: "10.00", Quantity: "1", Number: "H-1"},
+			},
+		},
+		{
+			Amount: "9.99", CurrencyCode: "USD", PaymentRequestID: "store-b", SellerPayPalAccountID: "b@example.com",
+			Items: []PaymentItem{{Name: "E-book", Amount: "9.99", Quantity: "1", ItemCategory: ItemCategoryDigital}},
+		},
+	}
+
+	// the legacy fields of the request are replaced by the payment requests
+	form := url.Values{
+		"PAYMENTREQUEST_0_AMT":     {"1.00"},
+		"PAYMENTREQUEST_0_DESC":    {"legacy"},
+		"L_PAYMENTREQUEST_0_NAME5": {"legacy"},
+		"RETURNURL":                {"https://example.com/return"},
+	}
+	if err := encodePaymentRequests(form, paymentRequests); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"PAYMENTREQUEST_0_AMT":              "25.00",
+		"PAYMENTREQUEST_0_SHIPPINGAMT":      "3.00",
+		"PAYMENTREQUEST_0_TAXAMT":           "2.00",
+		"PAYMENTREQUEST_0_SHIPTONAME":       "John Doe",
+		"L_PAYMENTREQUEST_0_NAME0":          "Shirt",
+		"L_PAYMENTREQUEST_0_QTY0":           "2",
+		"L_PAYMENTREQUEST_0_ITEMCATEGORY0":  "Physical",
+		"L_PAYMENTREQUEST_0_NUMBER1":        "H-1",
+		"PAYMENTREQUEST_1_AMT":              "9.99",
+		"PAYMENTREQUEST_1_PAYMENTREQUESTID": "store-b",
+		"L_PAYMENTREQUEST_1_NAME0":          "E-book",
+		"L_PAYMENTREQUEST_1_ITEMCATEGORY0":  "Digital",
+	}
+	for key, value := range want {
+		if got := form[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("encodePaymentRequests() %s = %v, want %v", key, got, value)
+		}
+	}
+	if _, ok := form["PAYMENTREQUEST_1_TAXAMT"]; ok {
+		t.Errorf("encodePaymentRequests() encodes empty PAYMENTREQUEST_1_TAXAMT")
+	}
+	for _, key := range []string{"PAYMENTREQUEST_0_DESC", "L_PAYMENTREQUEST_0_NAME5"} {
+		if _, ok := form[key]; ok {
+			t.Errorf("encodePaymentRequests() keeps legacy %s", key)
+		}
+	}
+	if form.Get("RETURNURL") == "" {
+		t.Errorf("encodePaymentRequests() deletes RETURNURL")
+	}
+
+	got, err := decodePaymentRequests(form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, paymentRequests) {
+		t.Errorf("decodePaymentRequests() got = %+v, want %+v", got, paymentRequests)
+	}
+}
+
+func TestClient_SetExpressCheckoutBillingAgreement(t *testing.T) {
+	c, s := newNVPTestClient(t, map[string]url.Values{
+		string(SetExpressCheckout): {"ACK": {AckSuccess}, "TOKEN": {"EC-7JY19224GB5421932"}},
+	})
+
+	resp, err := c.SetExpressCheckout(context.Background(), SetExpressCheckoutReq{
+		ReturnURL:   "https://example.com/return",
+		CancelURL:   "https://example.com/cancel",
+		Amount:      "1.00",
+		LocaleCode:  "de_DE",
+		BrandName:   "Example",
+		LandingPage: LandingPageBilling,
+		PaymentRequests: []PaymentRequest{
+			{Amount: "12.00", CurrencyCode: "EUR", Items: []PaymentItem{{Name: "Plan", Amount: "12.00", Quantity: "1"}}},
+		},
+		BillingAgreements: []BillingAgreement{
+			{BillingType: BillingTypeMerchantInitiatedBilling, Description: "Monthly plan"},
+		},
+	})
+	if err != nil || resp.Token != "EC-7JY19224GB5421932" {
+		t.Fatalf("SetExpressCheckout() got = %+v, err = %v", resp, err)
+	}
+
+	form := s.forms[0]
+	want := map[string]string{
+		"PAYMENTREQUEST_0_AMT":           "12.00",
+		"PAYMENTREQUEST_0_CURRENCYCODE":  "EUR",
+		"L_PAYMENTREQUEST_0_NAME0":       "Plan",
+		"L_BILLINGTYPE0":                 BillingTypeMerchantInitiatedBilling,
+		"L_BILLINGAGREEMENTDESCRIPTION0": "Monthly plan",
+		"LOCALECODE":                     "de_DE",
+		"BRANDNAME":                      "Example",
+		"LANDINGPAGE":                    "Billing",
+	}
+	for key, value := range want {
+		if got := form[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("SetExpressCheckout() %s = %v, want %v", key, got, value)
+		}
+	}
+	if _, ok := form["LOGOIMG"]; ok {
+		t.Errorf("SetExpressCheckout() encodes empty LOGOIMG")
+	}
+}
+
+func TestClient_DoExpressCheckoutPaymentItems(t *testing.T) {
+	c, s := newNVPTestClient(t, map[string]url.Values{
+		string(GetExpressCheckoutDetails): {
+			"ACK": {AckSuccess}, "TOKEN": {"EC-38A5689031140083A"}, "PAYERID": {"QKXQ7D7QCGYPW"},
+			"PAYMENTREQUEST_0_AMT": {"30.00"}, "PAYMENTREQUEST_0_ITEMAMT": {"30.00"}, "PAYMENTREQUEST_0_INVNUM": {"INV-2"},
+			"PAYMENTREQUEST_0_TRANSACTIONID": {""}, "PAYMENTREQUEST_0_ADDRESSSTATUS": {"Confirmed"},
+			"L_PAYMENTREQUEST_0_NAME0": {"A"}, "L_PAYMENTREQUEST_0_AMT0": {"10.00"}, "L_PAYMENTREQUEST_0_QTY0": {"1"},
+			"L_PAYMENTREQUEST_0_NAME1": {"B"}, "L_PAYMENTREQUEST_0_AMT1": {"10.00"}, "L_PAYMENTREQUEST_0_QTY1": {"1"},
+			"L_PAYMENTREQUEST_0_NAME2": {"C"}, "L_PAYMENTREQUEST_0_AMT2": {"10.00"}, "L_PAYMENTREQUEST_0_QTY2": {"1"},
+			"PAYMENTREQUEST_1_AMT": {"5.00"}, "PAYMENTREQUEST_1_PAYMENTREQUESTID": {"store-b"},
+		},
+		string(DoExpressCheckoutPayment): {"ACK": {AckSuccess}, "BILLINGAGREEMENTID": {"B-5YM95264NC0392934"}},
+	})
+
+	resp, err := c.DoExpressCheckoutPayment(context.Background(), GetExpressCheckoutDetailsReq{Token: "EC-38A5689031140083A"})
+	if err != nil || resp.BillingAgreementID != "B-5YM95264NC0392934" {
+		t.Fatalf("DoExpressCheckoutPayment() got = %+v, err = %v", resp, err)
+	}
+
+	form := s.forms[1]
+	for _, key := range []string{"L_PAYMENTREQUEST_0_NAME2", "L_PAYMENTREQUEST_0_AMT2", "PAYMENTREQUEST_1_AMT", "PAYMENTREQUEST_1_PAYMENTREQUESTID"} {
+		if form.Get(key) == "" {
+			t.Errorf("DoExpressCheckoutPayment() misses %s: %v", key, form)
+		}
+	}
+	if form.Get("MSGSUBID") != "INV-2" || form.Get("PAYERID") != "QKXQ7D7QCGYPW" {
+		t.Errorf("DoExpressCheckoutPayment() form = %v", form)
+	}
+	if _, ok := form["PAYMENTREQUEST_0_ADDRESSSTATUS"]; ok {
+		t.Errorf("DoExpressCheckoutPayment() copies response only field PAYMENTREQUEST_0_ADDRESSSTATUS")
+	}
+}
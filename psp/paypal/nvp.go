@@ -2,47 +2,102 @@ package paypal
 
 import (
 	"context"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 
-	"github.com/dghubble/sling"
+	"github.com/gorilla/schema"
+	"github.com/linhoi/kit/log"
 )
 
-func (c *Client) SetExpressCheckout(ctx context.Context, req SetExpressCheckoutReq) (SetExpressCheckoutResp, error) {
-	path := c.config.Host + nvp
+// ACK of NVP responses https://developer.paypal.com/docs/nvp-soap-api/NVPAPIOverview/#responses
+const (
+	AckSuccess            = "Success"
+	AckSuccessWithWarning = "SuccessWithWarning"
+	AckFailure            = "Failure"
+	AckFailureWithWarning = "FailureWithWarning"
+)
+
+// NVP requests and responses share the url tags of the fields.
+var (
+	nvpEncoder = newNVPEncoder()
+	nvpDecoder = newNVPDecoder()
+)
+
+func newNVPEncoder() *schema.Encoder {
+	encoder := schema.NewEncoder()
+	encoder.SetAliasTag("url")
+	return encoder
+}
 
-	request, err := sling.New().Post(path).BodyForm(setExpressCheckoutReq{
-		NVPBase:               c.NvpBase(SetExpressCheckout),
-		SetExpressCheckoutReq: req,
-	}).Request()
+func newNVPDecoder() *schema.Decoder {
+	decoder := schema.NewDecoder()
+	decoder.SetAliasTag("url")
+	decoder.IgnoreUnknownKeys(true)
+	return decoder
+}
 
+// nvpResponseDecoder decodes the url encoded body of NVP responses into *url.Values.
+type nvpResponseDecoder struct{}
 
-	client := http.Client{}
-	resp, err := client.Do(request)
+func (nvpResponseDecoder) Decode(resp *http.Response, v interface{}) error {
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Println(err)
-		return SetExpressCheckoutResp{}, err
+		return err
 	}
 
-	defer resp.Body.Close()
-
-	by ,err := ioutil.ReadAll(resp.Body)
+	values, err := url.ParseQuery(string(body))
 	if err != nil {
-		fmt.Println(err)
-		return SetExpressCheckoutResp{}, err
+		return err
+	}
+
+	*v.(*url.Values) = values
+	return nil
+}
+
+// nvpDo posts the form of method with the API credentials and decodes the response into resp,
+// a response whose ACK is not Success or SuccessWithWarning is returned as *NVPError.
+// The raw values are returned as well for fields resp does not cover.
+func (c *Client) nvpDo(ctx context.Context, method NVPMethod, form url.Values, resp interface{}) (url.Values, error) {
+	if c.apiErr != nil {
+		return nil, c.apiErr
+	}
+	if err := nvpEncoder.Encode(c.NvpBase(method), form); err != nil {
+		return nil, err
 	}
 
-	fmt.Println(string(by))
+	req, err := c.apiClient.New().Post(c.nvpEndpoint()).
+		Set("Content-Type", "application/x-www-form-urlencoded").
+		Body(strings.NewReader(form.Encode())).Request()
+	if err != nil {
+		return nil, err
+	}
 
-	value , err := url.ParseQuery(string(by))
+	var values url.Values
+	httpResp, err := c.apiClient.New().ResponseDecoder(nvpResponseDecoder{}).
+		Do(req.WithContext(ctx), &values, &values)
+	if err == nil && httpResp.StatusCode != http.StatusOK {
+		err = newAPIError(httpResp, APIError{Name: http.StatusText(httpResp.StatusCode), Message: values.Encode()})
+	}
+	if err == nil {
+		err = parseNVPError(values)
+	}
 	if err != nil {
-		fmt.Println(err)
-		return SetExpressCheckoutResp{}, err
+		log.S(ctx).Errorw(string(method), "err", err)
+
+		return values, err
+	}
+
+	if resp != nil {
+		if err = nvpDecoder.Decode(resp, values); err != nil {
+			log.S(ctx).Errorw(string(method), "decode", values.Encode(), "err", err)
+
+			return values, err
+		}
 	}
 
-	return SetExpressCheckoutResp{Body: value}, nil
+	return values, nil
 }
 
 func (c *Client) NvpBase(method NVPMethod) NVPBase {
@@ -55,99 +110,68 @@ func (c *Client) NvpBase(method NVPMethod) NVPBase {
 	}
 }
 
-
-func (c *Client) GetExpressCheckoutDetails(ctx context.Context, req GetExpressCheckoutDetailsReq) (GetExpressCheckoutDetailsResp, error) {
-	path := c.config.Host + nvp
-
-	request, err := sling.New().Post(path).BodyForm(getExpressCheckoutDetailsReq{
-		NVPBase:               c.NvpBase(GetExpressCheckoutDetails),
-		GetExpressCheckoutDetailsReq: req,
-	}).Request()
-
-
-	client := http.Client{}
-	resp, err := client.Do(request)
-	if err != nil {
-		fmt.Println(err)
-		return GetExpressCheckoutDetailsResp{}, err
+// SetExpressCheckout https://developer.paypal.com/docs/nvp-soap-api/set-express-checkout-nvp/
+func (c *Client) SetExpressCheckout(ctx context.Context, req SetExpressCheckoutReq) (resp SetExpressCheckoutResp, err error) {
+	form := url.Values{}
+	if err = nvpEncoder.Encode(req, form); err != nil {
+		return resp, err
+	}
+	if err = encodePaymentRequests(form, req.PaymentRequests); err != nil {
+		return resp, err
+	}
+	if err = encodeBillingAgreements(form, req.BillingAgreements); err != nil {
+		return resp, err
 	}
 
-	defer resp.Body.Close()
+	resp.Body, err = c.nvpDo(ctx, SetExpressCheckout, form, &resp)
+	return resp, err
+}
 
-	by ,err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println(err)
-		return GetExpressCheckoutDetailsResp{}, err
+// GetExpressCheckoutDetails https://developer.paypal.com/docs/nvp-soap-api/get-express-checkout-details-nvp/
+func (c *Client) GetExpressCheckoutDetails(ctx context.Context, req GetExpressCheckoutDetailsReq) (resp GetExpressCheckoutDetailsResp, err error) {
+	form := url.Values{}
+	if err = nvpEncoder.Encode(req, form); err != nil {
+		return resp, err
 	}
 
-	fmt.Println(string(by))
-
-	value , err := url.ParseQuery(string(by))
+	resp.Body, err = c.nvpDo(ctx, GetExpressCheckoutDetails, form, &resp)
 	if err != nil {
-		fmt.Println(err)
-		return GetExpressCheckoutDetailsResp{}, err
+		return resp, err
 	}
 
-	return GetExpressCheckoutDetailsResp{Body: value}, nil
+	resp.PaymentRequests, err = decodePaymentRequests(resp.Body)
+	return resp, err
 }
 
-func (c *Client) DoExpressCheckoutPayment(ctx context.Context, req GetExpressCheckoutDetailsReq) (GetExpressCheckoutDetailsResp, error) {
-	res ,err := c.GetExpressCheckoutDetails(ctx, req)
+// DoExpressCheckoutPayment completes the checkout with the details the payer approved.
+// https://developer.paypal.com/docs/nvp-soap-api/do-express-checkout-payment-nvp/
+func (c *Client) DoExpressCheckoutPayment(ctx context.Context, req GetExpressCheckoutDetailsReq) (resp DoExpressCheckoutPaymentResp, err error) {
+	details, err := c.GetExpressCheckoutDetails(ctx, req)
 	if err != nil {
-		fmt.Println(err)
-		return GetExpressCheckoutDetailsResp{},err
+		return resp, err
 	}
 
-	form := setFormForDoExpressCheckoutPayment(c.config.NVPAndSOAPAPICredentials, res.Body,req.Token)
-
-	client :=http.Client{}
-	path := c.config.Host + nvp
-	resp, err := client.PostForm(path, form)
-
-	defer resp.Body.Close()
-
-	by ,err := ioutil.ReadAll(resp.Body)
+	form, err := setFormForDoExpressCheckoutPayment(details)
 	if err != nil {
-		fmt.Println(err)
-		return GetExpressCheckoutDetailsResp{}, err
+		return resp, err
 	}
 
-	fmt.Println(string(by))
+	resp.Body, err = c.nvpDo(ctx, DoExpressCheckoutPayment, form, &resp)
+	return resp, err
+}
 
-	value , err := url.ParseQuery(string(by))
-	if err != nil {
-		fmt.Println(err)
-		return GetExpressCheckoutDetailsResp{}, err
+// setFormForDoExpressCheckoutPayment copies all the payment requests and items the payer approved.
+func setFormForDoExpressCheckoutPayment(details GetExpressCheckoutDetailsResp) (url.Values, error) {
+	form := url.Values{}
+	form.Set("TOKEN", details.Token)
+	form.Set("PAYERID", details.PayerID)
+	if err := encodePaymentRequests(form, details.PaymentRequests); err != nil {
+		return nil, err
+	}
+	// for duplicate
+	if len(details.PaymentRequests) > 0 && details.PaymentRequests[0].Invoice != "" {
+		form.Set("MSGSUBID", details.PaymentRequests[0].Invoice)
 	}
 
-	return GetExpressCheckoutDetailsResp{Body: value}, nil
+	return form, nil
 }
-
-func setFormForDoExpressCheckoutPayment(credentials APICredentials, details url.Values,token string) url.Values {
-	form := make(url.Values, 12)
-	form.Set("VERSION", string(Version124))
-	form.Set("USER", credentials.Username)
-	form.Set("PWD", credentials.Password)
-	form.Set("SIGNATURE", credentials.Signature)
-	form.Set("METHOD", "DoExpressCheckoutPayment")
-	form.Set("TOKEN", token)
-	form.Set("PAYMENTREQUEST_0_PAYMENTACTION", details.Get("PAYMENTREQUEST_0_PAYMENTACTION"))
-	form.Set("PAYERID", details.Get("PAYERID"))
-	form.Set("PAYMENTREQUEST_0_AMT", details.Get("PAYMENTREQUEST_0_AMT"))
-	form.Set("PAYMENTREQUEST_0_ITEMAMT", details.Get("PAYMENTREQUEST_0_ITEMAMT"))
-	form.Set("PAYMENTREQUEST_0_SHIPPINGAMT", details.Get("PAYMENTREQUEST_0_SHIPPINGAMT"))
-	form.Set("PAYMENTREQUEST_0_TAXAMT", details.Get("PAYMENTREQUEST_0_TAXAMT"))
-	form.Set("PAYMENTREQUEST_0_CURRENCYCODE", details.Get("PAYMENTREQUEST_0_CURRENCYCODE"))
-	form.Set("PAYMENTREQUEST_0_DESC", details.Get("PAYMENTREQUEST_0_DESC"))
-	form.Set("L_PAYMENTREQUEST_0_NAME0", details.Get("L_PAYMENTREQUEST_0_NAME0"))
-	form.Set("L_PAYMENTREQUEST_0_AMT0", details.Get("L_PAYMENTREQUEST_0_AMT0"))
-	form.Set("L_PAYMENTREQUEST_0_NUMBER0", details.Get("L_PAYMENTREQUEST_0_NUMBER0"))
-	form.Set("L_PAYMENTREQUEST_0_QTY0", details.Get("L_PAYMENTREQUEST_0_QTY0"))
-	form.Set("L_PAYMENTREQUEST_0_NAME1", details.Get("L_PAYMENTREQUEST_0_NAME1"))
-	form.Set("L_PAYMENTREQUEST_0_AMT1", details.Get("L_PAYMENTREQUEST_0_AMT1"))
-	form.Set("L_PAYMENTREQUEST_0_NUMBER1", details.Get("L_PAYMENTREQUEST_0_NUMBER1"))
-	form.Set("L_PAYMENTREQUEST_0_QTY1", details.Get("L_PAYMENTREQUEST_0_QTY1"))
-	// for duplicate
-	form.Set("MSGSUBID", details.Get("PAYMENTREQUEST_0_INVNUM"))
-	return form
-}
\ No newline at end of file
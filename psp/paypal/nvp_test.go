@@ -2,7 +2,12 @@ package paypal
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -82,7 +87,7 @@ func TestClient_DoExpressCheckoutPayment(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		want    GetExpressCheckoutDetailsResp
+		want    DoExpressCheckoutPaymentResp
 		wantErr bool
 	}{
 		{
@@ -104,3 +109,105 @@ func TestClient_DoExpressCheckoutPayment(t *testing.T) {
 		})
 	}
 }
+
+// nvpServer answers NVP requests by METHOD and records the forms it receives.
+type nvpServer struct {
+	mu        sync.Mutex
+	forms     []url.Values
+	responses map[string]url.Values
+}
+
+func (s *nvpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.URL.Path != nvp {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.forms = append(s.forms, r.PostForm)
+	s.mu.Unlock()
+
+	resp, ok := s.responses[r.PostForm.Get("METHOD")]
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write([]byte(resp.Encode()))
+}
+
+func newNVPTestClient(t *testing.T, responses map[string]url.Values) (*Client, *nvpServer) {
+	s := &nvpServer{responses: responses}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{Host: server.URL, NVPAndSOAPAPICredentials: APICredentials{
+		Username: "user", Password: "pwd", Signature: "sig",
+	}}), s
+}
+
+func TestClient_NVP(t *testing.T) {
+	c, s := newNVPTestClient(t, map[string]url.Values{
+		string(GetExpressCheckoutDetails): {
+			"ACK": {AckSuccess}, "CORRELATIONID": {"3a1f0c8e"}, "TOKEN": {"EC-38A5689031140083A"},
+			"PAYERID": {"QKXQ7D7QCGYPW"}, "CHECKOUTSTATUS": {"PaymentActionNotInitiated"},
+			"PAYMENTREQUEST_0_AMT": {"10.99"}, "PAYMENTREQUEST_0_CURRENCYCODE": {"USD"},
+			"PAYMENTREQUEST_0_PAYMENTACTION": {"Sale"}, "PAYMENTREQUEST_0_INVNUM": {"INV-1"},
+		},
+		string(DoExpressCheckoutPayment): {
+			"ACK": {AckSuccessWithWarning}, "TOKEN": {"EC-38A5689031140083A"},
+			"PAYMENTINFO_0_TRANSACTIONID": {"8UK57712DB8306034"}, "PAYMENTINFO_0_PAYMENTSTATUS": {"Completed"},
+			"PAYMENTINFO_0_AMT": {"10.99"}, "PAYMENTINFO_0_FEEAMT": {"0.62"},
+			"L_ERRORCODE0": {"11607"}, "L_SHORTMESSAGE0": {"Duplicate Request"},
+		},
+		string(SetExpressCheckout): {
+			"ACK": {AckFailure}, "CORRELATIONID": {"7b2e5d1a"},
+			"L_ERRORCODE0": {"10412"}, "L_SHORTMESSAGE0": {"Duplicate invoice"},
+			"L_LONGMESSAGE0": {"Payment has already been made for this InvoiceID."}, "L_SEVERITYCODE0": {"Error"},
+		},
+	})
+	ctx := context.Background()
+
+	payment, err := c.DoExpressCheckoutPayment(ctx, GetExpressCheckoutDetailsReq{Token: "EC-38A5689031140083A"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Ack != AckSuccessWithWarning || payment.TransactionID != "8UK57712DB8306034" ||
+		payment.PaymentStatus != "Completed" || payment.FeeAmount != "0.62" || payment.Body.Get("L_ERRORCODE0") != "11607" {
+		t.Errorf("DoExpressCheckoutPayment() got = %+v", payment)
+	}
+
+	if len(s.forms) != 2 {
+		t.Fatalf("DoExpressCheckoutPayment() sent %d requests, want 2", len(s.forms))
+	}
+	for _, form := range s.forms {
+		if form.Get("USER") != "user" || form.Get("PWD") != "pwd" || form.Get("SIGNATURE") != "sig" || form.Get("VERSION") != string(Version124) {
+			t.Errorf("%s credentials = %v", form.Get("METHOD"), form)
+		}
+	}
+	form := s.forms[1]
+	if form.Get("PAYERID") != "QKXQ7D7QCGYPW" || form.Get("PAYMENTREQUEST_0_AMT") != "10.99" || form.Get("MSGSUBID") != "INV-1" {
+		t.Errorf("DoExpressCheckoutPayment() form = %v", form)
+	}
+
+	_, err = c.SetExpressCheckout(ctx, SetExpressCheckoutReq{Amount: "10.99", CurrencyCode: "USD", Invoice: "INV-1"})
+	var nvpErr *NVPError
+	if !errors.As(err, &nvpErr) || nvpErr.Code() != "10412" || nvpErr.CorrelationID != "7b2e5d1a" {
+		t.Fatalf("SetExpressCheckout() err = %v, want NVPError 10412", err)
+	}
+	if !errors.Is(err, ErrDuplicateInvoiceID) || IsRetryable(err) {
+		t.Errorf("SetExpressCheckout() err = %v, want ErrDuplicateInvoiceID", err)
+	}
+	if got := s.forms[2].Get("PAYMENTREQUEST_0_INVNUM"); got != "INV-1" {
+		t.Errorf("SetExpressCheckout() PAYMENTREQUEST_0_INVNUM = %v", got)
+	}
+}
+
+func TestClient_NVPHTTPError(t *testing.T) {
+	c, _ := newNVPTestClient(t, nil)
+
+	_, err := c.GetExpressCheckoutDetails(context.Background(), GetExpressCheckoutDetailsReq{Token: "EC-38A5689031140083A"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError || !IsRetryable(err) {
+		t.Errorf("GetExpressCheckoutDetails() err = %v, want 500 APIError", err)
+	}
+}
@@ -0,0 +1,232 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// REFUNDTYPE
+const (
+	RefundTypeFull    = "Full"
+	RefundTypePartial = "Partial"
+)
+
+// searchWarningTruncated is the warning of TransactionSearch that returns the first 100 transactions only.
+const searchWarningTruncated = "11002"
+
+// RefundTransactionReq refunds a payment of express checkout or reference transaction.
+// RefundType is inferred from Amount if empty: Full without Amount, Partial otherwise.
+// A partial refund requires Amount and CurrencyCode.
+// https://developer.paypal.com/api/nvp-soap/refund-transaction-nvp/#request-fields
+type RefundTransactionReq struct {
+	TransactionID string `url:"TRANSACTIONID"`
+	RefundType    string `url:"REFUNDTYPE"`
+	Amount        string `url:"AMT,omitempty"`
+	CurrencyCode  string `url:"CURRENCYCODE,omitempty"`
+	Invoice       string `url:"INVOICEID,omitempty"`
+	Note          string `url:"NOTE,omitempty"`
+	// RefundSource is any, default, instant or eCheck.
+	RefundSource string `url:"REFUNDSOURCE,omitempty"`
+	// MsgSubID makes the request idempotent, a retry with the same id returns the first refund.
+	MsgSubID string `url:"MSGSUBID,omitempty"`
+}
+
+// RefundTransactionResp https://developer.paypal.com/api/nvp-soap/refund-transaction-nvp/#response-fields
+type RefundTransactionResp struct {
+	NVPResp
+	RefundTransactionID string `url:"REFUNDTRANSACTIONID"`
+	RefundStatus        string `url:"REFUNDSTATUS"`
+	PendingReason       string `url:"PENDINGREASON"`
+	FeeRefundAmount     string `url:"FEEREFUNDAMT"`
+	GrossRefundAmount   string `url:"GROSSREFUNDAMT"`
+	NetRefundAmount     string `url:"NETREFUNDAMT"`
+	TotalRefundedAmount string `url:"TOTALREFUNDEDAMOUNT"`
+	CurrencyCode        string `url:"CURRENCYCODE"`
+	MsgSubID            string `url:"MSGSUBID"`
+
+	Body url.Values `url:"-"`
+}
+
+// GetTransactionDetailsResp https://developer.paypal.com/api/nvp-soap/get-transaction-details-nvp/#response-fields
+type GetTransactionDetailsResp struct {
+	NVPResp
+	TransactionID       string `url:"TRANSACTIONID"`
+	ParentTransactionID string `url:"PARENTTRANSACTIONID"`
+	TransactionType     string `url:"TRANSACTIONTYPE"`
+	PaymentType         string `url:"PAYMENTTYPE"`
+	OrderTime           string `url:"ORDERTIME"`
+	Amount              string `url:"AMT"`
+	FeeAmount           string `url:"FEEAMT"`
+	TaxAmount           string `url:"TAXAMT"`
+	ShippingAmount      string `url:"SHIPPINGAMT"`
+	HandlingAmount      string `url:"HANDLINGAMT"`
+	CurrencyCode        string `url:"CURRENCYCODE"`
+	PaymentStatus       string `url:"PAYMENTSTATUS"`
+	PendingReason       string `url:"PENDINGREASON"`
+	ReasonCode          string `url:"REASONCODE"`
+	Invoice             string `url:"INVNUM"`
+	Custom              string `url:"CUSTOM"`
+	Note                string `url:"NOTE"`
+	Subject             string `url:"SUBJECT"`
+
+	ReceiverEmail string `url:"RECEIVEREMAIL"`
+	ReceiverID    string `url:"RECEIVERID"`
+	Email         string `url:"EMAIL"`
+	PayerID       string `url:"PAYERID"`
+	PayerStatus   string `url:"PAYERSTATUS"`
+	FirstName     string `url:"FIRSTNAME"`
+	LastName      string `url:"LASTNAME"`
+	CountryCode   string `url:"COUNTRYCODE"`
+
+	ShipToName        string `url:"SHIPTONAME"`
+	ShipToStreet      string `url:"SHIPTOSTREET"`
+	ShipToStreet2     string `url:"SHIPTOSTREET2"`
+	ShipToCity        string `url:"SHIPTOCITY"`
+	ShipToState       string `url:"SHIPTOSTATE"`
+	ShipToZip         string `url:"SHIPTOZIP"`
+	ShipToCountryCode string `url:"SHIPTOCOUNTRYCODE"`
+
+	// Items are decoded from L_NAMEn, L_AMTn...
+	Items []PaymentItem `url:"-"`
+
+	Body url.Values `url:"-"`
+}
+
+// NVPTransactionSearchReq searches the transactions since StartDate, which is required.
+// https://developer.paypal.com/api/nvp-soap/transaction-search-nvp/#request-fields
+type NVPTransactionSearchReq struct {
+	StartDate time.Time `url:"-"`
+	EndDate   time.Time `url:"-"`
+
+	TransactionID string `url:"TRANSACTIONID,omitempty"`
+	Email         string `url:"EMAIL,omitempty"`
+	Receiver      string `url:"RECEIVER,omitempty"`
+	ReceiptID     string `url:"RECEIPTID,omitempty"`
+	Invoice       string `url:"INVNUM,omitempty"`
+	ProfileID     string `url:"PROFILEID,omitempty"`
+	// TransactionClass is All, Sent, Received, Refund...
+	TransactionClass string `url:"TRANSACTIONCLASS,omitempty"`
+	Amount           string `url:"AMT,omitempty"`
+	CurrencyCode     string `url:"CURRENCYCODE,omitempty"`
+	// Status is Pending, Processing, Success, Denied or Reversed.
+	Status string `url:"STATUS,omitempty"`
+}
+
+// NVPTransaction is one of the L_*n transactions of TransactionSearch.
+type NVPTransaction struct {
+	Timestamp     string `url:"TIMESTAMP"`
+	Timezone      string `url:"TIMEZONE"`
+	Type          string `url:"TYPE"`
+	Email         string `url:"EMAIL"`
+	Name          string `url:"NAME"`
+	TransactionID string `url:"TRANSACTIONID"`
+	Status        string `url:"STATUS"`
+	Amount        string `url:"AMT"`
+	CurrencyCode  string `url:"CURRENCYCODE"`
+	FeeAmount     string `url:"FEEAMT"`
+	NetAmount     string `url:"NETAMT"`
+}
+
+type NVPTransactionSearchResp struct {
+	NVPResp
+	Transactions []NVPTransaction `url:"-"`
+	// Truncated is true if more than 100 transactions match, narrow the search to get the others.
+	Truncated bool `url:"-"`
+
+	Body url.Values `url:"-"`
+}
+
+// RefundTransaction https://developer.paypal.com/api/nvp-soap/refund-transaction-nvp/
+func (c *Client) RefundTransaction(ctx context.Context, req RefundTransactionReq) (resp RefundTransactionResp, err error) {
+	if req.RefundType == "" {
+		req.RefundType = RefundTypeFull
+		if req.Amount != "" {
+			req.RefundType = RefundTypePartial
+		}
+	}
+	if req.RefundType == RefundTypePartial && (req.Amount == "" || req.CurrencyCode == "") {
+		return resp, errors.New("amount or currencyCode of partial refund is zero")
+	}
+
+	form := url.Values{}
+	if err = nvpEncoder.Encode(req, form); err != nil {
+		return resp, err
+	}
+
+	resp.Body, err = c.nvpDo(ctx, RefundTransaction, form, &resp)
+	return resp, err
+}
+
+// GetTransactionDetails https://developer.paypal.com/api/nvp-soap/get-transaction-details-nvp/
+// Unlike the REST transaction search, the payment is available as soon as it completes.
+func (c *Client) GetTransactionDetails(ctx context.Context, transactionID string) (resp GetTransactionDetailsResp, err error) {
+	form := url.Values{}
+	form.Set("TRANSACTIONID", transactionID)
+
+	resp.Body, err = c.nvpDo(ctx, GetTransactionDetails, form, &resp)
+	if err != nil {
+		return resp, err
+	}
+
+	groups := decodeList(resp.Body)
+	for _, n := range sortedIndexes(groups) {
+		var item PaymentItem
+		if err = nvpDecoder.Decode(&item, groups[n]); err != nil {
+			return resp, err
+		}
+		if item != (PaymentItem{}) {
+			resp.Items = append(resp.Items, item)
+		}
+	}
+
+	return resp, nil
+}
+
+// TransactionSearch https://developer.paypal.com/api/nvp-soap/transaction-search-nvp/
+func (c *Client) TransactionSearch(ctx context.Context, req NVPTransactionSearchReq) (resp NVPTransactionSearchResp, err error) {
+	if req.StartDate.IsZero() {
+		return resp, errors.New("startDate is zero")
+	}
+
+	form := url.Values{}
+	if err = nvpEncoder.Encode(req, form); err != nil {
+		return resp, err
+	}
+	form.Set("STARTDATE", req.StartDate.UTC().Format(time.RFC3339))
+	if !req.EndDate.IsZero() {
+		form.Set("ENDDATE", req.EndDate.UTC().Format(time.RFC3339))
+	}
+
+	resp.Body, err = c.nvpDo(ctx, TransactionSearch, form, &resp)
+	if err != nil {
+		return resp, err
+	}
+
+	groups := decodeList(resp.Body)
+	for _, n := range sortedIndexes(groups) {
+		var transaction NVPTransaction
+		if err = nvpDecoder.Decode(&transaction, groups[n]); err != nil {
+			return resp, err
+		}
+		if transaction.TransactionID != "" {
+			resp.Transactions = append(resp.Transactions, transaction)
+		}
+	}
+	resp.Truncated = parseNVPWarning(resp.Body, searchWarningTruncated)
+
+	return resp, nil
+}
+
+// parseNVPWarning reports whether a response of SuccessWithWarning has the warning code.
+func parseNVPWarning(values url.Values, code string) bool {
+	for i := 0; values.Get("L_ERRORCODE"+strconv.Itoa(i)) != ""; i++ {
+		if values.Get("L_ERRORCODE"+strconv.Itoa(i)) == code {
+			return true
+		}
+	}
+
+	return false
+}
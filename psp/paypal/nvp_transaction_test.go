@@ -0,0 +1,133 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_RefundTransaction(t *testing.T) {
+	c, s := newNVPTestClient(t, map[string]url.Values{
+		string(RefundTransaction): {
+			"ACK": {AckSuccess}, "REFUNDTRANSACTIONID": {"2N9958441U564040B"}, "REFUNDSTATUS": {"Instant"},
+			"GROSSREFUNDAMT": {"5.00"}, "TOTALREFUNDEDAMOUNT": {"5.00"}, "CURRENCYCODE": {"USD"}, "MSGSUBID": {"refund-1"},
+		},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		req      RefundTransactionReq
+		wantType string
+		wantAmt  string
+	}{
+		{name: "full", req: RefundTransactionReq{TransactionID: "8UK57712DB8306034", MsgSubID: "refund-1"}, wantType: RefundTypeFull},
+		{name: "partial", req: RefundTransactionReq{TransactionID: "8UK57712DB8306034", Amount: "5.00", CurrencyCode: "USD"}, wantType: RefundTypePartial, wantAmt: "5.00"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refund, err := c.RefundTransaction(ctx, tt.req)
+			if err != nil || refund.RefundTransactionID != "2N9958441U564040B" || refund.GrossRefundAmount != "5.00" {
+				t.Fatalf("RefundTransaction() got = %+v, err = %v", refund, err)
+			}
+
+			form := s.forms[i]
+			if form.Get("REFUNDTYPE") != tt.wantType || form.Get("AMT") != tt.wantAmt || form.Get("MSGSUBID") != tt.req.MsgSubID {
+				t.Errorf("RefundTransaction() form = %v", form)
+			}
+		})
+	}
+}
+
+func TestClient_RefundTransactionInvalid(t *testing.T) {
+	c, s := newNVPTestClient(t, nil)
+
+	tests := []struct {
+		name string
+		req  RefundTransactionReq
+	}{
+		{name: "partial without currency", req: RefundTransactionReq{TransactionID: "8UK57712DB8306034", Amount: "5.00"}},
+		{name: "partial without amount", req: RefundTransactionReq{TransactionID: "8UK57712DB8306034", RefundType: RefundTypePartial, CurrencyCode: "USD"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.RefundTransaction(context.Background(), tt.req); err == nil {
+				t.Errorf("RefundTransaction() err = nil, want amount or currencyCode is zero")
+			}
+		})
+	}
+	if len(s.forms) != 0 {
+		t.Errorf("RefundTransaction() sent %d invalid requests", len(s.forms))
+	}
+}
+
+func TestClient_RefundTransactionRefused(t *testing.T) {
+	c, _ := newNVPTestClient(t, map[string]url.Values{
+		string(RefundTransaction): {
+			"ACK": {AckFailure}, "L_ERRORCODE0": {"10009"}, "L_SHORTMESSAGE0": {"Transaction refused"},
+			"L_LONGMESSAGE0": {"This transaction has already been fully refunded"},
+		},
+	})
+
+	_, err := c.RefundTransaction(context.Background(), RefundTransactionReq{TransactionID: "8UK57712DB8306034"})
+	if !errors.Is(err, ErrRefundNotAllowed) {
+		t.Errorf("RefundTransaction() err = %v, want %v", err, ErrRefundNotAllowed)
+	}
+}
+
+func TestClient_GetTransactionDetails(t *testing.T) {
+	c, _ := newNVPTestClient(t, map[string]url.Values{
+		string(GetTransactionDetails): {
+			"ACK": {AckSuccess}, "TRANSACTIONID": {"8UK57712DB8306034"}, "PAYMENTSTATUS": {"Completed"},
+			"AMT": {"20.00"}, "FEEAMT": {"0.88"}, "INVNUM": {"INV-1"}, "PAYERID": {"QKXQ7D7QCGYPW"},
+			"L_NAME0": {"Shirt"}, "L_AMT0": {"5.00"}, "L_QTY0": {"2"},
+			"L_NAME1": {"Hat"}, "L_AMT1": {"10.00"}, "L_QTY1": {"1"}, "L_NUMBER1": {"H-1"},
+		},
+	})
+
+	details, err := c.GetTransactionDetails(context.Background(), "8UK57712DB8306034")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.PaymentStatus != "Completed" || details.Invoice != "INV-1" || details.FeeAmount != "0.88" {
+		t.Errorf("GetTransactionDetails() got = %+v", details)
+	}
+	wantItems := []PaymentItem{{Name: "Shirt", Amount: "5.00", Quantity: "2"}, {Name: "Hat", Amount: "10.00", Quantity: "1", Number: "H-1"}}
+	if !reflect.DeepEqual(details.Items, wantItems) {
+		t.Errorf("GetTransactionDetails() items = %+v, want %+v", details.Items, wantItems)
+	}
+}
+
+func TestClient_TransactionSearch(t *testing.T) {
+	c, s := newNVPTestClient(t, map[string]url.Values{
+		string(TransactionSearch): {
+			"ACK": {AckSuccessWithWarning}, "L_ERRORCODE0": {"11002"}, "L_SHORTMESSAGE0": {"Search warning"},
+			"L_TRANSACTIONID0": {"8UK57712DB8306034"}, "L_STATUS0": {"Completed"}, "L_AMT0": {"20.00"}, "L_TYPE0": {"Payment"},
+			"L_TRANSACTIONID1": {"2N9958441U564040B"}, "L_STATUS1": {"Completed"}, "L_AMT1": {"-5.00"}, "L_TYPE1": {"Refund"},
+		},
+	})
+
+	start := time.Date(2021, 12, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	resp, err := c.TransactionSearch(context.Background(), NVPTransactionSearchReq{StartDate: start, Invoice: "INV-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Transactions) != 2 || resp.Transactions[1].Type != "Refund" || resp.Transactions[1].Amount != "-5.00" || !resp.Truncated {
+		t.Errorf("TransactionSearch() got = %+v", resp)
+	}
+
+	form := s.forms[0]
+	if form.Get("STARTDATE") != "2021-12-01T00:00:00Z" || form.Get("INVNUM") != "INV-1" {
+		t.Errorf("TransactionSearch() form = %v", form)
+	}
+	if _, ok := form["ENDDATE"]; ok {
+		t.Errorf("TransactionSearch() sends empty ENDDATE")
+	}
+
+	if _, err = c.TransactionSearch(context.Background(), NVPTransactionSearchReq{Invoice: "INV-1"}); err == nil || len(s.forms) != 1 {
+		t.Errorf("TransactionSearch() err = %v, want startDate is zero", err)
+	}
+}
@@ -0,0 +1,259 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	ordersPath = "/v2/checkout/orders" // https://developer.paypal.com/docs/api/orders/v2/
+
+	headerRequestID = "PayPal-Request-Id"
+	headerPrefer    = "Prefer"
+	preferFull      = "return=representation"
+)
+
+type OrderIntent string
+
+const (
+	OrderIntentCapture   OrderIntent = "CAPTURE"
+	OrderIntentAuthorize OrderIntent = "AUTHORIZE"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusCreated             OrderStatus = "CREATED"
+	OrderStatusSaved               OrderStatus = "SAVED"
+	OrderStatusApproved            OrderStatus = "APPROVED"
+	OrderStatusVoided              OrderStatus = "VOIDED"
+	OrderStatusCompleted           OrderStatus = "COMPLETED"
+	OrderStatusPayerActionRequired OrderStatus = "PAYER_ACTION_REQUIRED"
+)
+
+// Money https://developer.paypal.com/docs/api/orders/v2/#definition-money
+type Money struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+type AmountWithBreakdown struct {
+	CurrencyCode string           `json:"currency_code"`
+	Value        string           `json:"value"`
+	Breakdown    *AmountBreakdown `json:"breakdown,omitempty"`
+}
+
+type AmountBreakdown struct {
+	ItemTotal        *Money `json:"item_total,omitempty"`
+	Shipping         *Money `json:"shipping,omitempty"`
+	Handling         *Money `json:"handling,omitempty"`
+	TaxTotal         *Money `json:"tax_total,omitempty"`
+	Insurance        *Money `json:"insurance,omitempty"`
+	ShippingDiscount *Money `json:"shipping_discount,omitempty"`
+	Discount         *Money `json:"discount,omitempty"`
+}
+
+type Name struct {
+	GivenName string `json:"given_name,omitempty"`
+	Surname   string `json:"surname,omitempty"`
+	FullName  string `json:"full_name,omitempty"`
+}
+
+type Address struct {
+	AddressLine1 string `json:"address_line_1,omitempty"`
+	AddressLine2 string `json:"address_line_2,omitempty"`
+	AdminArea2   string `json:"admin_area_2,omitempty"`
+	AdminArea1   string `json:"admin_area_1,omitempty"`
+	PostalCode   string `json:"postal_code,omitempty"`
+	CountryCode  string `json:"country_code"`
+}
+
+type Payer struct {
+	PayerID      string   `json:"payer_id,omitempty"`
+	EmailAddress string   `json:"email_address,omitempty"`
+	Name         *Name    `json:"name,omitempty"`
+	Address      *Address `json:"address,omitempty"`
+}
+
+type Payee struct {
+	EmailAddress string `json:"email_address,omitempty"`
+	MerchantID   string `json:"merchant_id,omitempty"`
+}
+
+type Item struct {
+	Name        string `json:"name"`
+	UnitAmount  *Money `json:"unit_amount"`
+	Tax         *Money `json:"tax,omitempty"`
+	Quantity    string `json:"quantity"`
+	Description string `json:"description,omitempty"`
+	SKU         string `json:"sku,omitempty"`
+	Category    string `json:"category,omitempty"`
+}
+
+type ShippingDetail struct {
+	Name    *Name    `json:"name,omitempty"`
+	Address *Address `json:"address,omitempty"`
+}
+
+type PurchaseUnit struct {
+	ReferenceID    string               `json:"reference_id,omitempty"`
+	Amount         *AmountWithBreakdown `json:"amount,omitempty"`
+	Payee          *Payee               `json:"payee,omitempty"`
+	Description    string               `json:"description,omitempty"`
+	CustomID       string               `json:"custom_id,omitempty"`
+	InvoiceID      string               `json:"invoice_id,omitempty"`
+	SoftDescriptor string               `json:"soft_descriptor,omitempty"`
+	Items          []Item               `json:"items,omitempty"`
+	Shipping       *ShippingDetail      `json:"shipping,omitempty"`
+	Payments       *PaymentCollection   `json:"payments,omitempty"`
+}
+
+// PaymentCollection is the payments of a purchase unit, only returned in responses.
+type PaymentCollection struct {
+	Authorizations []Authorization `json:"authorizations,omitempty"`
+	Captures       []Capture       `json:"captures,omitempty"`
+}
+
+type Authorization struct {
+	ID             string              `json:"id"`
+	Status         AuthorizationStatus `json:"status"`
+	StatusDetails  *StatusDetails      `json:"status_details,omitempty"`
+	Amount         *Money              `json:"amount,omitempty"`
+	InvoiceID      string              `json:"invoice_id,omitempty"`
+	CustomID       string              `json:"custom_id,omitempty"`
+	ExpirationTime time.Time           `json:"expiration_time"`
+	CreateTime     time.Time           `json:"create_time"`
+	UpdateTime     time.Time           `json:"update_time"`
+	Links          []HATEOASLink       `json:"links,omitempty"`
+}
+
+type Capture struct {
+	ID            string         `json:"id"`
+	Status        CaptureStatus  `json:"status"`
+	StatusDetails *StatusDetails `json:"status_details,omitempty"`
+	Amount        *Money         `json:"amount,omitempty"`
+	InvoiceID     string         `json:"invoice_id,omitempty"`
+	CustomID      string         `json:"custom_id,omitempty"`
+	FinalCapture  bool           `json:"final_capture"`
+	CreateTime    time.Time      `json:"create_time"`
+	UpdateTime    time.Time      `json:"update_time"`
+	Links         []HATEOASLink  `json:"links,omitempty"`
+}
+
+// ApplicationContext https://developer.paypal.com/docs/api/orders/v2/#definition-order_application_context
+type ApplicationContext struct {
+	BrandName          string `json:"brand_name,omitempty"`
+	Locale             string `json:"locale,omitempty"`
+	LandingPage        string `json:"landing_page,omitempty"`
+	ShippingPreference string `json:"shipping_preference,omitempty"`
+	UserAction         string `json:"user_action,omitempty"`
+	ReturnURL          string `json:"return_url,omitempty"`
+	CancelURL          string `json:"cancel_url,omitempty"`
+}
+
+type CreateOrderReq struct {
+	// RequestID is sent as PayPal-Request-Id, requests with the same id create the order only once.
+	RequestID          string              `json:"-"`
+	Intent             OrderIntent         `json:"intent"`
+	Payer              *Payer              `json:"payer,omitempty"`
+	PurchaseUnits      []PurchaseUnit      `json:"purchase_units"`
+	PaymentSource      *PaymentSource      `json:"payment_source,omitempty"`
+	ApplicationContext *ApplicationContext `json:"application_context,omitempty"`
+}
+
+type Order struct {
+	ID            string         `json:"id"`
+	Status        OrderStatus    `json:"status"`
+	Intent        OrderIntent    `json:"intent"`
+	Payer         *Payer         `json:"payer,omitempty"`
+	PurchaseUnits []PurchaseUnit `json:"purchase_units"`
+	PaymentSource *PaymentSource `json:"payment_source,omitempty"`
+	CreateTime    time.Time      `json:"create_time"`
+	UpdateTime    time.Time      `json:"update_time"`
+	Links         []HATEOASLink  `json:"links"`
+}
+
+// ApproveURL returns the url the payer should be redirected to.
+func (o Order) ApproveURL() string {
+	return approveURL(o.Links)
+}
+
+func approveURL(links []HATEOASLink) string {
+	for _, link := range links {
+		if link.Rel == linkReasonTypeApprove || link.Rel == linkReasonTypePayerAction {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// PatchOperation https://developer.paypal.com/docs/api/orders/v2/#definition-patch
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON keeps zero values such as false or "", paypal rejects a replace without value,
+// the value is only left out for remove.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{Op: o.Op, Path: o.Path})
+	}
+
+	type operation PatchOperation
+	return json.Marshal(operation(o))
+}
+
+// CreateOrder https://developer.paypal.com/docs/api/orders/v2/#orders_create
+func (c *Client) CreateOrder(ctx context.Context, req CreateOrderReq) (order Order, err error) {
+	err = c.do(ctx, "CreateOrder", request{method: http.MethodPost, path: ordersPath, body: req, requestID: req.RequestID, prefer: true}, &order)
+	return order, err
+}
+
+// GetOrder https://developer.paypal.com/docs/api/orders/v2/#orders_get
+func (c *Client) GetOrder(ctx context.Context, orderID string) (order Order, err error) {
+	if orderID == "" {
+		return order, errors.New("orderID is zero")
+	}
+
+	err = c.do(ctx, "GetOrder", request{method: http.MethodGet, path: ordersPath + "/" + orderID}, &order)
+	return order, err
+}
+
+// PatchOrder updates an order in CREATED or APPROVED status.
+// https://developer.paypal.com/docs/api/orders/v2/#orders_patch
+func (c *Client) PatchOrder(ctx context.Context, orderID string, operations []PatchOperation) error {
+	if orderID == "" {
+		return errors.New("orderID is zero")
+	}
+
+	return c.do(ctx, "PatchOrder", request{method: http.MethodPatch, path: ordersPath + "/" + orderID, body: operations}, nil)
+}
+
+// AuthorizeOrder authorizes an approved order with intent AUTHORIZE.
+// https://developer.paypal.com/docs/api/orders/v2/#orders_authorize
+func (c *Client) AuthorizeOrder(ctx context.Context, orderID string, requestID string) (order Order, err error) {
+	return c.orderAction(ctx, "AuthorizeOrder", orderID, "/authorize", requestID)
+}
+
+// CaptureOrder captures an approved order with intent CAPTURE.
+// https://developer.paypal.com/docs/api/orders/v2/#orders_capture
+func (c *Client) CaptureOrder(ctx context.Context, orderID string, requestID string) (order Order, err error) {
+	return c.orderAction(ctx, "CaptureOrder", orderID, "/capture", requestID)
+}
+
+func (c *Client) orderAction(ctx context.Context, name, orderID, action, requestID string) (order Order, err error) {
+	if orderID == "" {
+		return order, errors.New("orderID is zero")
+	}
+
+	err = c.do(ctx, name, request{method: http.MethodPost, path: ordersPath + "/" + orderID + action, requestID: requestID, prefer: true}, &order)
+	return order, err
+}
@@ -0,0 +1,207 @@
+package paypal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// mockServer is a fake of the paypal REST endpoints, it serves the access token and the routes.
+type mockServer struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	routes   map[string]mockResponse
+}
+
+type mockResponse struct {
+	status int
+	body   interface{}
+}
+
+func (s *mockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == getAccessTokenPath {
+		_ = json.NewEncoder(w).Encode(AccessTokeResp{AccessToken: "A21AA-test", ExpiresIn: 32400})
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	body = bytes.TrimSpace(body)
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, body)
+	s.mu.Unlock()
+
+	resp, ok := s.routes[r.Method+" "+r.URL.Path]
+	if !ok {
+		resp = mockResponse{status: http.StatusNotFound, body: map[string]string{"name": "RESOURCE_NOT_FOUND"}}
+	}
+	if resp.status != 0 {
+		w.WriteHeader(resp.status)
+	}
+	if resp.body != nil {
+		_ = json.NewEncoder(w).Encode(resp.body)
+	}
+}
+
+func newMockClient(t *testing.T, routes map[string]mockResponse) (*Client, *mockServer) {
+	mock := &mockServer{routes: routes}
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{Host: server.URL, ClientID: "client", Secret: "secret"}), mock
+}
+
+func TestClient_CreateOrder(t *testing.T) {
+	order := map[string]interface{}{
+		"id":     "5O190127TN364715T",
+		"status": "CREATED",
+		"intent": "CAPTURE",
+		"links": []map[string]string{
+			{"href": "https://api-m.paypal.com/v2/checkout/orders/5O190127TN364715T", "rel": "self", "method": "GET"},
+			{"href": "https://www.paypal.com/checkoutnow?token=5O190127TN364715T", "rel": "approve", "method": "GET"},
+		},
+	}
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + ordersPath: {status: http.StatusCreated, body: order},
+	})
+
+	got, err := c.CreateOrder(context.Background(), CreateOrderReq{
+		RequestID: "order-1",
+		Intent:    OrderIntentCapture,
+		PurchaseUnits: []PurchaseUnit{
+			{ReferenceID: "ref-1", Amount: &AmountWithBreakdown{CurrencyCode: "USD", Value: "10.00"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "5O190127TN364715T" || got.Status != OrderStatusCreated {
+		t.Errorf("CreateOrder() got = %+v", got)
+	}
+	if got.ApproveURL() != "https://www.paypal.com/checkoutnow?token=5O190127TN364715T" {
+		t.Errorf("ApproveURL() got = %v", got.ApproveURL())
+	}
+
+	req := mock.requests[0]
+	if req.Header.Get(headerRequestID) != "order-1" {
+		t.Errorf("CreateOrder() PayPal-Request-Id = %v, want order-1", req.Header.Get(headerRequestID))
+	}
+	if req.Header.Get("Authorization") != "Bearer A21AA-test" {
+		t.Errorf("CreateOrder() Authorization = %v", req.Header.Get("Authorization"))
+	}
+
+	var body map[string]interface{}
+	_ = json.Unmarshal(mock.bodies[0], &body)
+	if _, ok := body["RequestID"]; ok {
+		t.Errorf("CreateOrder() body contains request id: %s", mock.bodies[0])
+	}
+	if body["intent"] != "CAPTURE" {
+		t.Errorf("CreateOrder() body intent = %v", body["intent"])
+	}
+}
+
+func TestClient_OrderActions(t *testing.T) {
+	captured := map[string]interface{}{
+		"id":     "5O190127TN364715T",
+		"status": "COMPLETED",
+		"purchase_units": []map[string]interface{}{{
+			"reference_id": "ref-1",
+			"payments": map[string]interface{}{
+				"captures": []map[string]interface{}{
+					{"id": "3C679366HH908993F", "status": "COMPLETED", "amount": map[string]string{"currency_code": "USD", "value": "10.00"}, "final_capture": true},
+				},
+			},
+		}},
+	}
+	authorized := map[string]interface{}{
+		"id":     "5O190127TN364715T",
+		"status": "COMPLETED",
+		"purchase_units": []map[string]interface{}{{
+			"payments": map[string]interface{}{
+				"authorizations": []map[string]interface{}{{"id": "0VF52814937998046", "status": "CREATED"}},
+			},
+		}},
+	}
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"GET " + ordersPath + "/5O190127TN364715T":            {body: map[string]string{"id": "5O190127TN364715T", "status": "APPROVED"}},
+		"PATCH " + ordersPath + "/5O190127TN364715T":          {status: http.StatusNoContent},
+		"POST " + ordersPath + "/5O190127TN364715T/capture":   {status: http.StatusCreated, body: captured},
+		"POST " + ordersPath + "/5O190127TN364715T/authorize": {status: http.StatusCreated, body: authorized},
+		"POST " + ordersPath + "/UNKNOWN/capture": {status: http.StatusUnprocessableEntity, body: map[string]interface{}{
+			"name": "UNPROCESSABLE_ENTITY", "details": []map[string]string{{"issue": "ORDER_NOT_APPROVED"}},
+		}},
+	})
+	ctx := context.Background()
+
+	order, err := c.GetOrder(ctx, "5O190127TN364715T")
+	if err != nil || order.Status != OrderStatusApproved {
+		t.Errorf("GetOrder() got = %+v, err = %v", order, err)
+	}
+
+	err = c.PatchOrder(ctx, "5O190127TN364715T", []PatchOperation{
+		{Op: "replace", Path: "/purchase_units/@reference_id=='ref-1'/amount", Value: Money{CurrencyCode: "USD", Value: "12.00"}},
+	})
+	if err != nil {
+		t.Errorf("PatchOrder() err = %v", err)
+	}
+
+	order, err = c.CaptureOrder(ctx, "5O190127TN364715T", "capture-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCaptures := []Capture{{ID: "3C679366HH908993F", Status: "COMPLETED", Amount: &Money{CurrencyCode: "USD", Value: "10.00"}, FinalCapture: true}}
+	if !reflect.DeepEqual(order.PurchaseUnits[0].Payments.Captures, wantCaptures) {
+		t.Errorf("CaptureOrder() captures = %+v, want %+v", order.PurchaseUnits[0].Payments.Captures, wantCaptures)
+	}
+
+	order, err = c.AuthorizeOrder(ctx, "5O190127TN364715T", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.PurchaseUnits[0].Payments.Authorizations[0].ID != "0VF52814937998046" {
+		t.Errorf("AuthorizeOrder() got = %+v", order)
+	}
+
+	if _, err = c.CaptureOrder(ctx, "UNKNOWN", ""); err == nil {
+		t.Errorf("CaptureOrder() want error for unapproved order")
+	}
+	if _, err = c.GetOrder(ctx, ""); err == nil {
+		t.Errorf("GetOrder() want error for empty order id")
+	}
+
+	wantHeaders := []string{"", "", "capture-1", "", ""}
+	var gotHeaders []string
+	for _, req := range mock.requests {
+		gotHeaders = append(gotHeaders, req.Header.Get(headerRequestID))
+	}
+	if !reflect.DeepEqual(gotHeaders, wantHeaders) {
+		t.Errorf("PayPal-Request-Id headers = %v, want %v", gotHeaders, wantHeaders)
+	}
+}
+
+func TestPatchOperation_MarshalJSON(t *testing.T) {
+	operations := []PatchOperation{
+		{Op: "replace", Path: "/purchase_units/@reference_id=='ref-1'/soft_descriptor", Value: ""},
+		{Op: "replace", Path: "/auto_bill_outstanding", Value: false},
+		{Op: "remove", Path: "/purchase_units/@reference_id=='ref-1'/shipping/address"},
+	}
+	got, err := json.Marshal(operations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[{"op":"replace","path":"/purchase_units/@reference_id=='ref-1'/soft_descriptor","value":""},` +
+		`{"op":"replace","path":"/auto_bill_outstanding","value":false},` +
+		`{"op":"remove","path":"/purchase_units/@reference_id=='ref-1'/shipping/address"}]`
+	if string(got) != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,222 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dghubble/sling"
+)
+
+// ErrStopPaging is returned by the callback of the Walk* methods to stop at the current page without an error.
+var ErrStopPaging = errors.New("paypal: stop paging")
+
+// Limiter throttles the page requests of the Walk* methods, *rate.Limiter of golang.org/x/time/rate implements it.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+const maxRateLimitRetries = 3
+
+// rateLimitBackoff is the wait before requesting a rate limited page again, it doubles on each retry.
+var rateLimitBackoff = time.Second
+
+// pagination tells the pager where the next page is.
+type pagination struct {
+	links []HATEOASLink
+	// page and totalPages are only returned by the endpoints paged by number.
+	page       int
+	totalPages int
+	items      int
+}
+
+// pageResp is the response of a list endpoint.
+type pageResp interface {
+	pagination() pagination
+}
+
+// pager lists the pages of an endpoint from the page of query until the last one.
+type pager struct {
+	name    string
+	path    string
+	query   interface{}
+	newPage func() pageResp
+}
+
+// SetPageLimiter throttles the page requests of the Walk* methods, e.g. by rate.NewLimiter(rate.Every(time.Second), 1).
+func (c *Client) SetPageLimiter(limiter Limiter) {
+	c.limiter = limiter
+}
+
+// walk calls fn with each page, it follows the next link of a page,
+// or requests page+1 until total_pages if the page has no next link.
+func (c *Client) walk(ctx context.Context, p pager, fn func(resp pageResp) error) error {
+	path, err := pageURL(p.path, p.query)
+	if err != nil {
+		return err
+	}
+
+	requested := make(map[string]bool)
+	for path != "" && !requested[path] {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		requested[path] = true
+
+		resp := p.newPage()
+		if err = c.getPage(ctx, p.name, path, resp); err != nil {
+			return err
+		}
+		if err = fn(resp); err != nil {
+			if errors.Is(err, ErrStopPaging) {
+				return nil
+			}
+			return err
+		}
+
+		path = nextPageURL(path, resp.pagination())
+	}
+
+	return nil
+}
+
+// getPage waits for the limiter and requests the page again if paypal throttles the requests.
+func (c *Client) getPage(ctx context.Context, name, path string, resp pageResp) error {
+	backoff := rateLimitBackoff
+	for retry := 0; ; retry++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		err := c.do(ctx, name, request{method: http.MethodGet, path: path}, resp)
+		if err == nil || !IsRateLimited(err) || retry == maxRateLimitRetries {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// pageURL returns path with the query encoded.
+func pageURL(path string, query interface{}) (string, error) {
+	if query == nil {
+		return path, nil
+	}
+
+	req, err := sling.New().Get(path).QueryStruct(query).Request()
+	if err != nil {
+		return "", err
+	}
+	return req.URL.String(), nil
+}
+
+// nextPageURL returns the url of the page after the current one, or empty on the last page.
+// A next link is requested on Config.Host, the access token is never sent to the host of the link.
+func nextPageURL(current string, p pagination) string {
+	if p.items == 0 {
+		return ""
+	}
+
+	for _, link := range p.links {
+		if link.Rel != linkReasonTypeNext {
+			continue
+		}
+		u, err := url.Parse(link.Href)
+		if err != nil {
+			return ""
+		}
+		return u.RequestURI()
+	}
+
+	if p.totalPages == 0 {
+		return ""
+	}
+	u, err := url.Parse(current)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	page := p.page
+	if page == 0 {
+		page, _ = strconv.Atoi(q.Get("page"))
+	}
+	if page == 0 {
+		page = 1
+	}
+	if page >= p.totalPages {
+		return ""
+	}
+	q.Set("page", strconv.Itoa(page+1))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (r ListDisputesResp) pagination() pagination {
+	return pagination{links: r.Links, items: len(r.Items)}
+}
+
+func (r TransactionSearchResp) pagination() pagination {
+	return pagination{links: r.Links, page: r.Page, totalPages: r.TotalPages, items: len(r.TransactionDetails)}
+}
+
+func (r ListProductsResp) pagination() pagination {
+	return pagination{links: r.Links, totalPages: r.TotalPages, items: len(r.Products)}
+}
+
+func (r ListPlansResp) pagination() pagination {
+	return pagination{links: r.Links, totalPages: r.TotalPages, items: len(r.Plans)}
+}
+
+func (r ListSubscriptionTransactionsResp) pagination() pagination {
+	return pagination{links: r.Links, totalPages: r.TotalPages, items: len(r.Transactions)}
+}
+
+// WalkDisputes calls fn with each page of the disputes matching req,
+// until the last page, ctx is done or fn returns an error.
+func (c *Client) WalkDisputes(ctx context.Context, req ListDisputesReq, fn func(page ListDisputesResp) error) error {
+	return c.walk(ctx, pager{name: "WalkDisputes", path: listDisputesPath, query: req,
+		newPage: func() pageResp { return &ListDisputesResp{} }}, func(resp pageResp) error {
+		return fn(*resp.(*ListDisputesResp))
+	})
+}
+
+// WalkTransactions calls fn with each page of the transactions matching req, starting from page 1 if req.Page is zero.
+// The time range between StartDate and EndDate must be less than 31 days.
+func (c *Client) WalkTransactions(ctx context.Context, req TransactionSearchReq, fn func(page TransactionSearchResp) error) error {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	return c.walk(ctx, pager{name: "WalkTransactions", path: getTransactionPath, query: req,
+		newPage: func() pageResp { return &TransactionSearchResp{} }}, func(resp pageResp) error {
+		return fn(*resp.(*TransactionSearchResp))
+	})
+}
+
+// WalkProducts calls fn with each page of the products.
+func (c *Client) WalkProducts(ctx context.Context, req ListReq, fn func(page ListProductsResp) error) error {
+	return c.walk(ctx, pager{name: "WalkProducts", path: productsPath, query: req,
+		newPage: func() pageResp { return &ListProductsResp{} }}, func(resp pageResp) error {
+		return fn(*resp.(*ListProductsResp))
+	})
+}
+
+// WalkPlans calls fn with each page of the plans matching req.
+func (c *Client) WalkPlans(ctx context.Context, req ListPlansReq, fn func(page ListPlansResp) error) error {
+	return c.walk(ctx, pager{name: "WalkPlans", path: plansPath, query: req,
+		newPage: func() pageResp { return &ListPlansResp{} }}, func(resp pageResp) error {
+		return fn(*resp.(*ListPlansResp))
+	})
+}
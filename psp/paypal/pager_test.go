@@ -0,0 +1,189 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pagingServer serves 3 pages of disputes by next links and 3 pages of transactions by page number,
+// the dispute pages are shorter than page_size, page 2 of the transactions is throttled once.
+func pagingServer(t *testing.T) (*Client, *int32) {
+	var requests int32
+	var throttled int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == getAccessTokenPath {
+			_ = json.NewEncoder(w).Encode(AccessTokeResp{AccessToken: "A21AA-test", ExpiresIn: 32400})
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case listDisputesPath:
+			if query.Get("dispute_state") != disputeStateRESOLVED {
+				t.Errorf("ListDisputes() query = %v, want dispute_state", query)
+			}
+			token := query.Get("next_page_token")
+			next, _ := strconv.Atoi(token)
+			next++
+			resp := map[string]interface{}{"items": []map[string]string{{"dispute_id": "PP-D-" + strconv.Itoa(next)}}}
+			switch {
+			case token == "fail":
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			case next < 3:
+				// the next link is on the production host, it must be requested on Config.Host
+				resp["links"] = []HATEOASLink{{Rel: linkReasonTypeNext, Method: http.MethodGet,
+					Href: "https://api-m.paypal.com" + listDisputesPath + "?dispute_state=RESOLVED&page_size=50&next_page_token=" + strconv.Itoa(next)}}
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case getTransactionPath:
+			page, _ := strconv.Atoi(query.Get("page"))
+			if page == 2 && atomic.AddInt32(&throttled, 1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"name":"RATE_LIMIT_REACHED"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"transaction_details": []map[string]interface{}{
+					{"transaction_info": map[string]string{"transaction_id": "TX" + strconv.Itoa(page)}},
+				},
+				"page": page, "total_pages": 3,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{Host: server.URL, ClientID: "client", Secret: "secret"}), &requests
+}
+
+type countLimiter struct {
+	waits int32
+}
+
+func (l *countLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.waits, 1)
+	return ctx.Err()
+}
+
+func TestClient_ListDisputesPages(t *testing.T) {
+	c, requests := pagingServer(t)
+
+	items, err := c.ListDisputes(context.Background())
+	if err != nil || len(items) != 3 || items[2].DisputeID != "PP-D-3" {
+		t.Fatalf("ListDisputes() got = %+v, err = %v", items, err)
+	}
+	if *requests != 3 {
+		t.Errorf("ListDisputes() requested %d pages, want 3", *requests)
+	}
+
+	items, next, err := c.ListOnePageDisputes(context.Background(), "1")
+	if err != nil || len(items) != 1 || next != "2" {
+		t.Errorf("ListOnePageDisputes() got = %+v, next = %v, err = %v", items, next, err)
+	}
+	if _, _, err = c.ListOnePageDisputes(context.Background(), "fail"); err == nil {
+		t.Errorf("ListOnePageDisputes() err = nil, want error")
+	}
+}
+
+func TestClient_WalkDisputes(t *testing.T) {
+	c, _ := pagingServer(t)
+	ctx := context.Background()
+
+	var pages int
+	err := c.WalkDisputes(ctx, ListDisputesReq{DisputeState: disputeStateRESOLVED}, func(page ListDisputesResp) error {
+		pages++
+		return ErrStopPaging
+	})
+	if err != nil || pages != 1 {
+		t.Errorf("WalkDisputes() pages = %d, err = %v, want stop at 1", pages, err)
+	}
+
+	wantErr := errors.New("store failed")
+	err = c.WalkDisputes(ctx, ListDisputesReq{DisputeState: disputeStateRESOLVED}, func(page ListDisputesResp) error {
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("WalkDisputes() err = %v, want %v", err, wantErr)
+	}
+
+	err = c.WalkDisputes(ctx, ListDisputesReq{DisputeState: disputeStateRESOLVED, NextPageToken: "fail"}, func(page ListDisputesResp) error {
+		return nil
+	})
+	if !IsRetryable(err) {
+		t.Errorf("WalkDisputes() err = %v, want 500", err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	pages = 0
+	err = c.WalkDisputes(cancelCtx, ListDisputesReq{DisputeState: disputeStateRESOLVED}, func(page ListDisputesResp) error {
+		pages++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || pages != 1 {
+		t.Errorf("WalkDisputes() pages = %d, err = %v, want %v", pages, err, context.Canceled)
+	}
+}
+
+func TestClient_WalkTransactions(t *testing.T) {
+	backoff := rateLimitBackoff
+	rateLimitBackoff = time.Millisecond
+	defer func() { rateLimitBackoff = backoff }()
+
+	c, requests := pagingServer(t)
+	limiter := &countLimiter{}
+	c.SetPageLimiter(limiter)
+
+	var ids []string
+	err := c.WalkTransactions(context.Background(), TransactionSearchReq{StartDate: "2021-12-01T00:00:00Z", EndDate: "2021-12-31T00:00:00Z"},
+		func(page TransactionSearchResp) error {
+			for _, detail := range page.TransactionDetails {
+				ids = append(ids, detail.TransactionInfo.TransactionId)
+			}
+			return nil
+		})
+	if err != nil || len(ids) != 3 || ids[0] != "TX1" || ids[2] != "TX3" {
+		t.Fatalf("WalkTransactions() got = %v, err = %v", ids, err)
+	}
+	// page 2 is requested again after it is throttled
+	if *requests != 4 || limiter.waits != 4 {
+		t.Errorf("WalkTransactions() requests = %d, limiter waits = %d, want 4", *requests, limiter.waits)
+	}
+}
+
+func Test_nextPageURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		p       pagination
+		want    string
+	}{
+		{name: "next link", current: "/v1/customer/disputes", want: "/v1/customer/disputes?next_page_token=abc",
+			p: pagination{items: 1, links: []HATEOASLink{{Rel: "self", Href: "https://api-m.paypal.com/v1/customer/disputes"},
+				{Rel: "next", Href: "https://api-m.paypal.com/v1/customer/disputes?next_page_token=abc"}}}},
+		{name: "page number", current: "/v1/reporting/transactions?page=1&page_size=500", p: pagination{items: 1, page: 1, totalPages: 2},
+			want: "/v1/reporting/transactions?page=2&page_size=500"},
+		{name: "page from query", current: "/v1/catalogs/products?page=2", p: pagination{items: 1, totalPages: 3},
+			want: "/v1/catalogs/products?page=3"},
+		{name: "last page", current: "/v1/reporting/transactions?page=2", p: pagination{items: 1, page: 2, totalPages: 2}},
+		{name: "empty page", current: "/v1/customer/disputes", p: pagination{links: []HATEOASLink{{Rel: "next", Href: "/v1/customer/disputes?next_page_token=abc"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(tt.current, tt.p); got != tt.want {
+				t.Errorf("nextPageURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	capturesPath       = "/v2/payments/captures/"       // https://developer.paypal.com/docs/api/payments/v2/#captures
+	authorizationsPath = "/v2/payments/authorizations/" // https://developer.paypal.com/docs/api/payments/v2/#authorizations
+	refundsPath        = "/v2/payments/refunds/"        // https://developer.paypal.com/docs/api/payments/v2/#refunds
+)
+
+type CaptureStatus string
+
+const (
+	CaptureStatusCompleted         CaptureStatus = "COMPLETED"
+	CaptureStatusDeclined          CaptureStatus = "DECLINED"
+	CaptureStatusPartiallyRefunded CaptureStatus = "PARTIALLY_REFUNDED"
+	CaptureStatusPending           CaptureStatus = "PENDING"
+	CaptureStatusRefunded          CaptureStatus = "REFUNDED"
+	CaptureStatusFailed            CaptureStatus = "FAILED"
+)
+
+type AuthorizationStatus string
+
+const (
+	AuthorizationStatusCreated           AuthorizationStatus = "CREATED"
+	AuthorizationStatusCaptured          AuthorizationStatus = "CAPTURED"
+	AuthorizationStatusDenied            AuthorizationStatus = "DENIED"
+	AuthorizationStatusExpired           AuthorizationStatus = "EXPIRED"
+	AuthorizationStatusPartiallyCaptured AuthorizationStatus = "PARTIALLY_CAPTURED"
+	AuthorizationStatusVoided            AuthorizationStatus = "VOIDED"
+	AuthorizationStatusPending           AuthorizationStatus = "PENDING"
+)
+
+type RefundStatus string
+
+const (
+	RefundStatusCancelled RefundStatus = "CANCELLED"
+	RefundStatusFailed    RefundStatus = "FAILED"
+	RefundStatusPending   RefundStatus = "PENDING"
+	RefundStatusCompleted RefundStatus = "COMPLETED"
+)
+
+// StatusDetails is the reason of a PENDING or DECLINED payment.
+type StatusDetails struct {
+	Reason string `json:"reason"`
+}
+
+// Refund https://developer.paypal.com/docs/api/payments/v2/#definition-refund
+type Refund struct {
+	ID            string         `json:"id"`
+	Status        RefundStatus   `json:"status"`
+	StatusDetails *StatusDetails `json:"status_details,omitempty"`
+	Amount        *Money         `json:"amount,omitempty"`
+	InvoiceID     string         `json:"invoice_id,omitempty"`
+	NoteToPayer   string         `json:"note_to_payer,omitempty"`
+	CreateTime    time.Time      `json:"create_time"`
+	UpdateTime    time.Time      `json:"update_time"`
+	Links         []HATEOASLink  `json:"links,omitempty"`
+}
+
+// RefundCaptureReq refunds the full capture when Amount is nil.
+type RefundCaptureReq struct {
+	// RequestID is sent as PayPal-Request-Id, requests with the same id refund only once.
+	RequestID   string `json:"-"`
+	Amount      *Money `json:"amount,omitempty"`
+	InvoiceID   string `json:"invoice_id,omitempty"`
+	NoteToPayer string `json:"note_to_payer,omitempty"`
+}
+
+// CaptureAuthorizationReq captures the full authorization when Amount is nil.
+type CaptureAuthorizationReq struct {
+	// RequestID is sent as PayPal-Request-Id, requests with the same id capture only once.
+	RequestID      string `json:"-"`
+	Amount         *Money `json:"amount,omitempty"`
+	InvoiceID      string `json:"invoice_id,omitempty"`
+	FinalCapture   bool   `json:"final_capture"`
+	NoteToPayer    string `json:"note_to_payer,omitempty"`
+	SoftDescriptor string `json:"soft_descriptor,omitempty"`
+}
+
+// ReauthorizeReq reauthorizes the original amount when Amount is nil.
+type ReauthorizeReq struct {
+	// RequestID is sent as PayPal-Request-Id, requests with the same id reauthorize only once.
+	RequestID string `json:"-"`
+	Amount    *Money `json:"amount,omitempty"`
+}
+
+// GetCapture https://developer.paypal.com/docs/api/payments/v2/#captures_get
+func (c *Client) GetCapture(ctx context.Context, captureID string) (capture Capture, err error) {
+	if captureID == "" {
+		return capture, errors.New("captureID is zero")
+	}
+
+	err = c.do(ctx, "GetCapture", request{method: http.MethodGet, path: capturesPath + captureID}, &capture)
+	return capture, err
+}
+
+// RefundCapture refunds a capture in full or partially.
+// https://developer.paypal.com/docs/api/payments/v2/#captures_refund
+func (c *Client) RefundCapture(ctx context.Context, captureID string, req RefundCaptureReq) (refund Refund, err error) {
+	if captureID == "" {
+		return refund, errors.New("captureID is zero")
+	}
+
+	err = c.do(ctx, "RefundCapture", request{method: http.MethodPost, path: capturesPath + captureID + "/refund", body: req, requestID: req.RequestID, prefer: true}, &refund)
+	return refund, err
+}
+
+// GetRefund https://developer.paypal.com/docs/api/payments/v2/#refunds_get
+func (c *Client) GetRefund(ctx context.Context, refundID string) (refund Refund, err error) {
+	if refundID == "" {
+		return refund, errors.New("refundID is zero")
+	}
+
+	err = c.do(ctx, "GetRefund", request{method: http.MethodGet, path: refundsPath + refundID}, &refund)
+	return refund, err
+}
+
+// GetAuthorization https://developer.paypal.com/docs/api/payments/v2/#authorizations_get
+func (c *Client) GetAuthorization(ctx context.Context, authorizationID string) (authorization Authorization, err error) {
+	if authorizationID == "" {
+		return authorization, errors.New("authorizationID is zero")
+	}
+
+	err = c.do(ctx, "GetAuthorization", request{method: http.MethodGet, path: authorizationsPath + authorizationID}, &authorization)
+	return authorization, err
+}
+
+// CaptureAuthorization captures an authorized payment in full or partially.
+// https://developer.paypal.com/docs/api/payments/v2/#authorizations_capture
+func (c *Client) CaptureAuthorization(ctx context.Context, authorizationID string, req CaptureAuthorizationReq) (capture Capture, err error) {
+	if authorizationID == "" {
+		return capture, errors.New("authorizationID is zero")
+	}
+
+	err = c.do(ctx, "CaptureAuthorization", request{method: http.MethodPost, path: authorizationsPath + authorizationID + "/capture", body: req, requestID: req.RequestID, prefer: true}, &capture)
+	return capture, err
+}
+
+// VoidAuthorization voids an authorized payment, captured authorizations can not be voided.
+// https://developer.paypal.com/docs/api/payments/v2/#authorizations_void
+func (c *Client) VoidAuthorization(ctx context.Context, authorizationID string) error {
+	if authorizationID == "" {
+		return errors.New("authorizationID is zero")
+	}
+
+	return c.do(ctx, "VoidAuthorization", request{method: http.MethodPost, path: authorizationsPath + authorizationID + "/void", prefer: true}, nil)
+}
+
+// ReauthorizeAuthorization reauthorizes an authorized payment after its three-day honor period.
+// https://developer.paypal.com/docs/api/payments/v2/#authorizations_reauthorize
+func (c *Client) ReauthorizeAuthorization(ctx context.Context, authorizationID string, req ReauthorizeReq) (authorization Authorization, err error) {
+	if authorizationID == "" {
+		return authorization, errors.New("authorizationID is zero")
+	}
+
+	err = c.do(ctx, "ReauthorizeAuthorization", request{method: http.MethodPost, path: authorizationsPath + authorizationID + "/reauthorize", body: req, requestID: req.RequestID, prefer: true}, &authorization)
+	return authorization, err
+}
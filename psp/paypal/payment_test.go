@@ -0,0 +1,136 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClient_RefundCapture(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + capturesPath + "2GG279541U471931P/refund": {status: http.StatusCreated, body: map[string]interface{}{
+			"id": "1JU08902781691411", "status": "COMPLETED", "amount": map[string]string{"currency_code": "USD", "value": "10.99"},
+		}},
+		"POST " + capturesPath + "REFUNDED/refund": {status: http.StatusUnprocessableEntity, body: map[string]interface{}{
+			"name":    "UNPROCESSABLE_ENTITY",
+			"details": []map[string]string{{"issue": "CAPTURE_FULLY_REFUNDED", "description": "The capture has already been fully refunded"}},
+		}},
+		"POST " + capturesPath + "PARTIAL/refund": {status: http.StatusUnprocessableEntity, body: map[string]interface{}{
+			"name":    "UNPROCESSABLE_ENTITY",
+			"details": []map[string]string{{"issue": "REFUND_AMOUNT_EXCEEDED"}},
+		}},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		captureID string
+		req       RefundCaptureReq
+		wantBody  string
+		wantErr   error
+	}{
+		{
+			name:      "full refund",
+			captureID: "2GG279541U471931P",
+			req:       RefundCaptureReq{RequestID: "refund-1"},
+			wantBody:  `{}`,
+		},
+		{
+			name:      "partial refund",
+			captureID: "2GG279541U471931P",
+			req:       RefundCaptureReq{RequestID: "refund-2", Amount: &Money{CurrencyCode: "USD", Value: "1.00"}, NoteToPayer: "sorry"},
+			wantBody:  `{"amount":{"currency_code":"USD","value":"1.00"},"note_to_payer":"sorry"}`,
+		},
+		{
+			name:      "fully refunded",
+			captureID: "REFUNDED",
+			wantBody:  `{}`,
+			wantErr:   ErrCaptureFullyRefunded,
+		},
+		{
+			name:      "amount exceeded",
+			captureID: "PARTIAL",
+			req:       RefundCaptureReq{Amount: &Money{CurrencyCode: "USD", Value: "100.00"}},
+			wantBody:  `{"amount":{"currency_code":"USD","value":"100.00"}}`,
+			wantErr:   ErrRefundAmountExceeded,
+		},
+		{
+			name:      "unknown capture",
+			captureID: "UNKNOWN",
+			wantBody:  `{}`,
+			wantErr:   ErrResourceNotFound,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refund, err := c.RefundCapture(ctx, tt.captureID, tt.req)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("RefundCapture() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (refund.ID != "1JU08902781691411" || refund.Status != RefundStatusCompleted) {
+				t.Errorf("RefundCapture() got = %+v", refund)
+			}
+
+			if got := string(mock.bodies[i]); got != tt.wantBody {
+				t.Errorf("RefundCapture() body = %s, want %s", got, tt.wantBody)
+			}
+			if got := mock.requests[i].Header.Get(headerRequestID); got != tt.req.RequestID {
+				t.Errorf("RefundCapture() PayPal-Request-Id = %v, want %v", got, tt.req.RequestID)
+			}
+		})
+	}
+}
+
+func TestClient_Authorization(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"GET " + authorizationsPath + "0VF52814937998046": {body: map[string]interface{}{"id": "0VF52814937998046", "status": "CREATED"}},
+		"POST " + authorizationsPath + "0VF52814937998046/capture": {status: http.StatusCreated, body: map[string]interface{}{
+			"id": "2GG279541U471931P", "status": "PENDING", "status_details": map[string]string{"reason": "PENDING_REVIEW"}, "final_capture": true,
+		}},
+		"POST " + authorizationsPath + "0VF52814937998046/reauthorize": {status: http.StatusCreated, body: map[string]interface{}{"id": "8AA831015G517922L", "status": "CREATED"}},
+		"POST " + authorizationsPath + "0VF52814937998046/void":        {status: http.StatusNoContent},
+		"POST " + authorizationsPath + "EXPIRED/capture": {status: http.StatusUnprocessableEntity, body: map[string]interface{}{
+			"name": "UNPROCESSABLE_ENTITY", "details": []map[string]string{{"issue": "AUTHORIZATION_EXPIRED"}},
+		}},
+		"POST " + authorizationsPath + "CAPTURED/void": {status: http.StatusUnprocessableEntity, body: map[string]interface{}{
+			"name": "UNPROCESSABLE_ENTITY", "details": []map[string]string{{"issue": "PREVIOUSLY_CAPTURED"}},
+		}},
+	})
+	ctx := context.Background()
+
+	authorization, err := c.GetAuthorization(ctx, "0VF52814937998046")
+	if err != nil || authorization.Status != AuthorizationStatusCreated {
+		t.Errorf("GetAuthorization() got = %+v, err = %v", authorization, err)
+	}
+
+	capture, err := c.CaptureAuthorization(ctx, "0VF52814937998046", CaptureAuthorizationReq{RequestID: "capture-1", FinalCapture: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capture.Status != CaptureStatusPending || capture.StatusDetails == nil || capture.StatusDetails.Reason != "PENDING_REVIEW" {
+		t.Errorf("CaptureAuthorization() got = %+v", capture)
+	}
+	var body map[string]interface{}
+	_ = json.Unmarshal(mock.bodies[1], &body)
+	if body["final_capture"] != true {
+		t.Errorf("CaptureAuthorization() body = %s", mock.bodies[1])
+	}
+
+	authorization, err = c.ReauthorizeAuthorization(ctx, "0VF52814937998046", ReauthorizeReq{Amount: &Money{CurrencyCode: "USD", Value: "10.99"}})
+	if err != nil || authorization.ID != "8AA831015G517922L" {
+		t.Errorf("ReauthorizeAuthorization() got = %+v, err = %v", authorization, err)
+	}
+
+	if err = c.VoidAuthorization(ctx, "0VF52814937998046"); err != nil {
+		t.Errorf("VoidAuthorization() err = %v", err)
+	}
+
+	if _, err = c.CaptureAuthorization(ctx, "EXPIRED", CaptureAuthorizationReq{}); !errors.Is(err, ErrAuthorizationExpired) {
+		t.Errorf("CaptureAuthorization() err = %v, want %v", err, ErrAuthorizationExpired)
+	}
+	if err = c.VoidAuthorization(ctx, "CAPTURED"); !errors.Is(err, ErrAuthorizationCaptured) {
+		t.Errorf("VoidAuthorization() err = %v, want %v", err, ErrAuthorizationCaptured)
+	}
+}
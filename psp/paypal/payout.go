@@ -0,0 +1,224 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	payoutsPath     = "/v1/payments/payouts"      // https://developer.paypal.com/docs/api/payments.payouts-batch/v1/
+	payoutItemsPath = "/v1/payments/payouts-item" // https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item
+	// maxPayoutItems is the max number of items in a batch payout.
+	maxPayoutItems = 15000
+)
+
+type BatchStatus string
+
+const (
+	BatchStatusDenied     BatchStatus = "DENIED"
+	BatchStatusPending    BatchStatus = "PENDING"
+	BatchStatusProcessing BatchStatus = "PROCESSING"
+	BatchStatusSuccess    BatchStatus = "SUCCESS"
+	BatchStatusCanceled   BatchStatus = "CANCELED"
+)
+
+// PayoutItemStatus https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#definition-payout_transaction_status
+type PayoutItemStatus string
+
+const (
+	PayoutItemStatusSuccess   PayoutItemStatus = "SUCCESS"
+	PayoutItemStatusFailed    PayoutItemStatus = "FAILED"
+	PayoutItemStatusPending   PayoutItemStatus = "PENDING"
+	PayoutItemStatusUnclaimed PayoutItemStatus = "UNCLAIMED"
+	PayoutItemStatusReturned  PayoutItemStatus = "RETURNED"
+	PayoutItemStatusOnHold    PayoutItemStatus = "ONHOLD"
+	PayoutItemStatusBlocked   PayoutItemStatus = "BLOCKED"
+	PayoutItemStatusRefunded  PayoutItemStatus = "REFUNDED"
+	PayoutItemStatusReversed  PayoutItemStatus = "REVERSED"
+)
+
+type RecipientType string
+
+const (
+	RecipientTypeEmail    RecipientType = "EMAIL"
+	RecipientTypePhone    RecipientType = "PHONE"
+	RecipientTypePayPalID RecipientType = "PAYPAL_ID"
+)
+
+type RecipientWallet string
+
+const (
+	RecipientWalletPayPal RecipientWallet = "PAYPAL"
+	RecipientWalletVenmo  RecipientWallet = "VENMO"
+)
+
+var payoutAmountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,2})?$`)
+
+// zeroDecimalCurrencies have no fraction in the amounts.
+// https://developer.paypal.com/api/rest/reference/currency-codes/
+var zeroDecimalCurrencies = map[string]bool{"HUF": true, "JPY": true, "TWD": true}
+
+// PayoutAmount is the amount of payouts, which is named currency instead of currency_code.
+type PayoutAmount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// validate checks the amount is positive with the decimals of the currency.
+func (a PayoutAmount) validate() error {
+	if len(a.Currency) != 3 || strings.ToUpper(a.Currency) != a.Currency {
+		return fmt.Errorf("currency %q is invalid", a.Currency)
+	}
+	if !payoutAmountPattern.MatchString(a.Value) || strings.Trim(a.Value, "0.") == "" {
+		return fmt.Errorf("amount %q is invalid", a.Value)
+	}
+	if zeroDecimalCurrencies[a.Currency] && strings.Contains(a.Value, ".") {
+		return fmt.Errorf("amount %q of %s has decimals", a.Value, a.Currency)
+	}
+	return nil
+}
+
+type SenderBatchHeader struct {
+	// SenderBatchID identifies the batch, paypal rejects a sender_batch_id used in the last 30 days.
+	SenderBatchID string        `json:"sender_batch_id"`
+	RecipientType RecipientType `json:"recipient_type,omitempty"`
+	EmailSubject  string        `json:"email_subject,omitempty"`
+	EmailMessage  string        `json:"email_message,omitempty"`
+}
+
+type PayoutItem struct {
+	RecipientType RecipientType `json:"recipient_type,omitempty"`
+	Amount        PayoutAmount  `json:"amount"`
+	Note          string        `json:"note,omitempty"`
+	// Receiver is the email, phone or paypal id of the recipient by RecipientType.
+	Receiver        string          `json:"receiver"`
+	SenderItemID    string          `json:"sender_item_id,omitempty"`
+	RecipientWallet RecipientWallet `json:"recipient_wallet,omitempty"`
+}
+
+type CreatePayoutReq struct {
+	SenderBatchHeader SenderBatchHeader `json:"sender_batch_header"`
+	Items             []PayoutItem      `json:"items"`
+}
+
+func (r CreatePayoutReq) validate() error {
+	if r.SenderBatchHeader.SenderBatchID == "" {
+		return errors.New("senderBatchID is zero")
+	}
+	if len(r.Items) == 0 || len(r.Items) > maxPayoutItems {
+		return fmt.Errorf("payout has %d items, want 1 to %d", len(r.Items), maxPayoutItems)
+	}
+	for i, item := range r.Items {
+		if item.Receiver == "" {
+			return fmt.Errorf("items[%d]: receiver is empty", i)
+		}
+		if err := item.Amount.validate(); err != nil {
+			return fmt.Errorf("items[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+type PayoutBatchHeader struct {
+	PayoutBatchID     string            `json:"payout_batch_id"`
+	BatchStatus       BatchStatus       `json:"batch_status"`
+	TimeCreated       time.Time         `json:"time_created"`
+	TimeCompleted     time.Time         `json:"time_completed"`
+	SenderBatchHeader SenderBatchHeader `json:"sender_batch_header"`
+	Amount            *PayoutAmount     `json:"amount,omitempty"`
+	Fees              *PayoutAmount     `json:"fees,omitempty"`
+}
+
+// PayoutItemError is the reason of a failed, returned or unclaimed item.
+type PayoutItemError struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// PayoutItemDetail https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get
+type PayoutItemDetail struct {
+	PayoutItemID      string           `json:"payout_item_id"`
+	TransactionID     string           `json:"transaction_id"`
+	TransactionStatus PayoutItemStatus `json:"transaction_status"`
+	PayoutItemFee     *PayoutAmount    `json:"payout_item_fee,omitempty"`
+	PayoutBatchID     string           `json:"payout_batch_id"`
+	SenderBatchID     string           `json:"sender_batch_id"`
+	PayoutItem        PayoutItem       `json:"payout_item"`
+	TimeProcessed     time.Time        `json:"time_processed"`
+	Errors            *PayoutItemError `json:"errors,omitempty"`
+	Links             []HATEOASLink    `json:"links"`
+}
+
+// PayoutBatch is a batch payout with one page of the items.
+type PayoutBatch struct {
+	BatchHeader PayoutBatchHeader  `json:"batch_header"`
+	Items       []PayoutItemDetail `json:"items"`
+	TotalItems  int                `json:"total_items"`
+	TotalPages  int                `json:"total_pages"`
+	Links       []HATEOASLink      `json:"links"`
+}
+
+func (b PayoutBatch) pagination() pagination {
+	return pagination{links: b.Links, totalPages: b.TotalPages, items: len(b.Items)}
+}
+
+// CreatePayout sends the items of a batch payout, the batch is processed asynchronously.
+// SenderBatchID is sent as PayPal-Request-Id, so retrying the request returns the same batch.
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts_post
+func (c *Client) CreatePayout(ctx context.Context, req CreatePayoutReq) (batch PayoutBatch, err error) {
+	if err = req.validate(); err != nil {
+		return batch, err
+	}
+
+	err = c.do(ctx, "CreatePayout", request{method: http.MethodPost, path: payoutsPath, body: req, requestID: req.SenderBatchHeader.SenderBatchID}, &batch)
+	return batch, err
+}
+
+// GetPayoutBatch returns the batch with one page of the items.
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts_get
+func (c *Client) GetPayoutBatch(ctx context.Context, payoutBatchID string, req ListReq) (batch PayoutBatch, err error) {
+	if payoutBatchID == "" {
+		return batch, errors.New("payoutBatchID is zero")
+	}
+
+	err = c.do(ctx, "GetPayoutBatch", request{method: http.MethodGet, path: payoutsPath + "/" + payoutBatchID, query: req}, &batch)
+	return batch, err
+}
+
+// WalkPayoutBatch calls fn with each page of the items of a batch.
+func (c *Client) WalkPayoutBatch(ctx context.Context, payoutBatchID string, req ListReq, fn func(page PayoutBatch) error) error {
+	if payoutBatchID == "" {
+		return errors.New("payoutBatchID is zero")
+	}
+
+	return c.walk(ctx, pager{name: "WalkPayoutBatch", path: payoutsPath + "/" + payoutBatchID, query: req,
+		newPage: func() pageResp { return &PayoutBatch{} }}, func(resp pageResp) error {
+		return fn(*resp.(*PayoutBatch))
+	})
+}
+
+// GetPayoutItem https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_get
+func (c *Client) GetPayoutItem(ctx context.Context, payoutItemID string) (item PayoutItemDetail, err error) {
+	if payoutItemID == "" {
+		return item, errors.New("payoutItemID is zero")
+	}
+
+	err = c.do(ctx, "GetPayoutItem", request{method: http.MethodGet, path: payoutItemsPath + "/" + payoutItemID}, &item)
+	return item, err
+}
+
+// CancelPayoutItem cancels an UNCLAIMED item, the amount is returned to the sender.
+// https://developer.paypal.com/docs/api/payments.payouts-batch/v1/#payouts-item_cancel
+func (c *Client) CancelPayoutItem(ctx context.Context, payoutItemID string) (item PayoutItemDetail, err error) {
+	if payoutItemID == "" {
+		return item, errors.New("payoutItemID is zero")
+	}
+
+	err = c.do(ctx, "CancelPayoutItem", request{method: http.MethodPost, path: payoutItemsPath + "/" + payoutItemID + "/cancel"}, &item)
+	return item, err
+}
@@ -0,0 +1,107 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClient_CreatePayout(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + payoutsPath: {status: http.StatusCreated, body: map[string]interface{}{
+			"batch_header": map[string]interface{}{
+				"payout_batch_id": "5UXD2E8A7EBQJ", "batch_status": "PENDING",
+				"sender_batch_header": map[string]string{"sender_batch_id": "affiliate-2021-12"},
+			},
+		}},
+		"GET " + payoutsPath + "/5UXD2E8A7EBQJ": {body: map[string]interface{}{
+			"batch_header": map[string]interface{}{"payout_batch_id": "5UXD2E8A7EBQJ", "batch_status": "SUCCESS",
+				"amount": map[string]string{"value": "10.00", "currency": "USD"}},
+			"items": []map[string]interface{}{{
+				"payout_item_id": "8AELMXH8UB2P8", "transaction_status": "UNCLAIMED",
+				"payout_item": map[string]interface{}{"receiver": "affiliate@example.com", "amount": map[string]string{"value": "10.00", "currency": "USD"}},
+				"errors":      map[string]string{"name": "RECEIVER_UNREGISTERED", "message": "Receiver is unregistered"},
+			}},
+		}},
+		"GET " + payoutItemsPath + "/8AELMXH8UB2P8":         {body: map[string]interface{}{"payout_item_id": "8AELMXH8UB2P8", "transaction_status": "UNCLAIMED"}},
+		"POST " + payoutItemsPath + "/8AELMXH8UB2P8/cancel": {body: map[string]interface{}{"payout_item_id": "8AELMXH8UB2P8", "transaction_status": "RETURNED"}},
+	})
+	ctx := context.Background()
+
+	batch, err := c.CreatePayout(ctx, CreatePayoutReq{
+		SenderBatchHeader: SenderBatchHeader{SenderBatchID: "affiliate-2021-12", EmailSubject: "You have a payout!"},
+		Items: []PayoutItem{
+			{RecipientType: RecipientTypeEmail, Receiver: "affiliate@example.com", Amount: PayoutAmount{Value: "10.00", Currency: "USD"}, SenderItemID: "commission-1"},
+			{RecipientType: RecipientTypePhone, Receiver: "4087811638", Amount: PayoutAmount{Value: "1000", Currency: "JPY"}},
+		},
+	})
+	if err != nil || batch.BatchHeader.PayoutBatchID != "5UXD2E8A7EBQJ" || batch.BatchHeader.BatchStatus != BatchStatusPending {
+		t.Fatalf("CreatePayout() got = %+v, err = %v", batch, err)
+	}
+	if got := mock.requests[0].Header.Get(headerRequestID); got != "affiliate-2021-12" {
+		t.Errorf("CreatePayout() PayPal-Request-Id = %v, want sender_batch_id", got)
+	}
+	if body := string(mock.bodies[0]); !strings.Contains(body, `"amount":{"value":"10.00","currency":"USD"}`) {
+		t.Errorf("CreatePayout() body = %s", body)
+	}
+
+	batch, err = c.GetPayoutBatch(ctx, "5UXD2E8A7EBQJ", ListReq{PageSize: 100})
+	if err != nil || batch.BatchHeader.BatchStatus != BatchStatusSuccess || len(batch.Items) != 1 ||
+		batch.Items[0].TransactionStatus != PayoutItemStatusUnclaimed || batch.Items[0].Errors.Name != "RECEIVER_UNREGISTERED" {
+		t.Fatalf("GetPayoutBatch() got = %+v, err = %v", batch, err)
+	}
+
+	var pages int
+	err = c.WalkPayoutBatch(ctx, "5UXD2E8A7EBQJ", ListReq{}, func(page PayoutBatch) error {
+		pages++
+		return nil
+	})
+	if err != nil || pages != 1 {
+		t.Errorf("WalkPayoutBatch() pages = %d, err = %v", pages, err)
+	}
+
+	item, err := c.GetPayoutItem(ctx, "8AELMXH8UB2P8")
+	if err != nil || item.TransactionStatus != PayoutItemStatusUnclaimed {
+		t.Errorf("GetPayoutItem() got = %+v, err = %v", item, err)
+	}
+	item, err = c.CancelPayoutItem(ctx, "8AELMXH8UB2P8")
+	if err != nil || item.TransactionStatus != PayoutItemStatusReturned {
+		t.Errorf("CancelPayoutItem() got = %+v, err = %v", item, err)
+	}
+	if _, err = c.CancelPayoutItem(ctx, "UNKNOWN"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("CancelPayoutItem() err = %v, want %v", err, ErrResourceNotFound)
+	}
+}
+
+func TestCreatePayoutReq_validate(t *testing.T) {
+	item := func(value, currency string) PayoutItem {
+		return PayoutItem{Receiver: "affiliate@example.com", Amount: PayoutAmount{Value: value, Currency: currency}}
+	}
+	header := SenderBatchHeader{SenderBatchID: "affiliate-2021-12"}
+
+	tests := []struct {
+		name string
+		req  CreatePayoutReq
+		want string
+	}{
+		{name: "valid", req: CreatePayoutReq{SenderBatchHeader: header, Items: []PayoutItem{item("0.01", "USD"), item("500", "JPY")}}},
+		{name: "no sender batch id", req: CreatePayoutReq{Items: []PayoutItem{item("1.00", "USD")}}, want: "senderBatchID is zero"},
+		{name: "no items", req: CreatePayoutReq{SenderBatchHeader: header}, want: "payout has 0 items"},
+		{name: "no receiver", req: CreatePayoutReq{SenderBatchHeader: header, Items: []PayoutItem{{Amount: PayoutAmount{Value: "1.00", Currency: "USD"}}}}, want: "items[0]: receiver is empty"},
+		{name: "zero", req: CreatePayoutReq{SenderBatchHeader: header, Items: []PayoutItem{item("0.00", "USD")}}, want: `amount "0.00" is invalid`},
+		{name: "negative", req: CreatePayoutReq{SenderBatchHeader: header, Items: []PayoutItem{item("-1.00", "USD")}}, want: `amount "-1.00" is invalid`},
+		{name: "three decimals", req: CreatePayoutReq{SenderBatchHeader: header, Items: []PayoutItem{item("1.005", "USD")}}, want: `amount "1.005" is invalid`},
+		{name: "zero decimal currency", req: CreatePayoutReq{SenderBatchHeader: header, Items: []PayoutItem{item("1.00", "USD"), item("100.5", "JPY")}}, want: "items[1]: amount \"100.5\" of JPY has decimals"},
+		{name: "currency", req: CreatePayoutReq{SenderBatchHeader: header, Items: []PayoutItem{item("1.00", "usd")}}, want: `currency "usd" is invalid`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.validate()
+			if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("validate() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
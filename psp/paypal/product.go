@@ -0,0 +1,93 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const productsPath = "/v1/catalogs/products" // https://developer.paypal.com/docs/api/catalog-products/v1/
+
+type ProductType string
+
+const (
+	ProductTypePhysical ProductType = "PHYSICAL"
+	ProductTypeDigital  ProductType = "DIGITAL"
+	ProductTypeService  ProductType = "SERVICE"
+)
+
+// Product is the goods or service a billing plan is created for.
+// https://developer.paypal.com/docs/api/catalog-products/v1/#products_get
+type Product struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Type        ProductType   `json:"type"`
+	Category    string        `json:"category,omitempty"`
+	ImageURL    string        `json:"image_url,omitempty"`
+	HomeURL     string        `json:"home_url,omitempty"`
+	CreateTime  time.Time     `json:"create_time"`
+	UpdateTime  time.Time     `json:"update_time"`
+	Links       []HATEOASLink `json:"links,omitempty"`
+}
+
+type CreateProductReq struct {
+	// RequestID is sent as PayPal-Request-Id, requests with the same id create the product only once.
+	RequestID string `json:"-"`
+	// ID is generated by paypal if empty.
+	ID          string      `json:"id,omitempty"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Type        ProductType `json:"type"`
+	Category    string      `json:"category,omitempty"`
+	ImageURL    string      `json:"image_url,omitempty"`
+	HomeURL     string      `json:"home_url,omitempty"`
+}
+
+// ListReq is the page of list requests, the page starts from 1.
+type ListReq struct {
+	Page          int  `url:"page,omitempty"`
+	PageSize      int  `url:"page_size,omitempty"`
+	TotalRequired bool `url:"total_required,omitempty"`
+}
+
+type ListProductsResp struct {
+	Products   []Product     `json:"products"`
+	TotalItems int           `json:"total_items"`
+	TotalPages int           `json:"total_pages"`
+	Links      []HATEOASLink `json:"links"`
+}
+
+// CreateProduct https://developer.paypal.com/docs/api/catalog-products/v1/#products_create
+func (c *Client) CreateProduct(ctx context.Context, req CreateProductReq) (product Product, err error) {
+	err = c.do(ctx, "CreateProduct", request{method: http.MethodPost, path: productsPath, body: req, requestID: req.RequestID, prefer: true}, &product)
+	return product, err
+}
+
+// ListProducts lists one page of the products.
+// https://developer.paypal.com/docs/api/catalog-products/v1/#products_list
+func (c *Client) ListProducts(ctx context.Context, req ListReq) (resp ListProductsResp, err error) {
+	err = c.do(ctx, "ListProducts", request{method: http.MethodGet, path: productsPath, query: req}, &resp)
+	return resp, err
+}
+
+// GetProduct https://developer.paypal.com/docs/api/catalog-products/v1/#products_get
+func (c *Client) GetProduct(ctx context.Context, productID string) (product Product, err error) {
+	if productID == "" {
+		return product, errors.New("productID is zero")
+	}
+
+	err = c.do(ctx, "GetProduct", request{method: http.MethodGet, path: productsPath + "/" + productID}, &product)
+	return product, err
+}
+
+// UpdateProduct updates the description, category, image_url or home_url of a product.
+// https://developer.paypal.com/docs/api/catalog-products/v1/#products_patch
+func (c *Client) UpdateProduct(ctx context.Context, productID string, operations []PatchOperation) error {
+	if productID == "" {
+		return errors.New("productID is zero")
+	}
+
+	return c.do(ctx, "UpdateProduct", request{method: http.MethodPatch, path: productsPath + "/" + productID, body: operations}, nil)
+}
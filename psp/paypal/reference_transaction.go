@@ -0,0 +1,142 @@
+package paypal
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// BILLINGAGREEMENTSTATUS
+const (
+	BillingAgreementStatusActive   = "Active"
+	BillingAgreementStatusCanceled = "Canceled"
+)
+
+// CreateBillingAgreementReq creates the billing agreement the payer accepted in express checkout,
+// the checkout is set with SetExpressCheckoutReq.BillingAgreements.
+type CreateBillingAgreementReq struct {
+	Token string `url:"TOKEN"`
+}
+
+type CreateBillingAgreementResp struct {
+	NVPResp
+	BillingAgreementID string `url:"BILLINGAGREEMENTID"`
+
+	Body url.Values `url:"-"`
+}
+
+// DoReferenceTransactionReq charges the payer with a billing agreement id, or the transaction id of a previous payment.
+// https://developer.paypal.com/api/nvp-soap/do-reference-transaction-nvp/#request-fields
+type DoReferenceTransactionReq struct {
+	ReferenceID   string `url:"REFERENCEID"`
+	PaymentAction string `url:"PAYMENTACTION"`
+	Amount        string `url:"AMT"`
+	CurrencyCode  string `url:"CURRENCYCODE"`
+
+	ItemAmount     string `url:"ITEMAMT,omitempty"`
+	ShippingAmount string `url:"SHIPPINGAMT,omitempty"`
+	HandlingAmount string `url:"HANDLINGAMT,omitempty"`
+	TaxAmount      string `url:"TAXAMT,omitempty"`
+	Desc           string `url:"DESC,omitempty"`
+	Custom         string `url:"CUSTOM,omitempty"`
+	Invoice        string `url:"INVNUM,omitempty"`
+	NotifyURL      string `url:"NOTIFYURL,omitempty"`
+	IPAddress      string `url:"IPADDRESS,omitempty"`
+	// MsgSubID makes the request idempotent, a retry with the same id returns the first transaction.
+	MsgSubID string `url:"MSGSUBID,omitempty"`
+
+	// Items are encoded as L_NAMEn, L_AMTn...
+	Items []PaymentItem `url:"-"`
+}
+
+// DoReferenceTransactionResp https://developer.paypal.com/api/nvp-soap/do-reference-transaction-nvp/#response-fields
+type DoReferenceTransactionResp struct {
+	NVPResp
+	BillingAgreementID string `url:"BILLINGAGREEMENTID"`
+	TransactionID      string `url:"TRANSACTIONID"`
+	TransactionType    string `url:"TRANSACTIONTYPE"`
+	PaymentType        string `url:"PAYMENTTYPE"`
+	OrderTime          string `url:"ORDERTIME"`
+	Amount             string `url:"AMT"`
+	FeeAmount          string `url:"FEEAMT"`
+	TaxAmount          string `url:"TAXAMT"`
+	CurrencyCode       string `url:"CURRENCYCODE"`
+	PaymentStatus      string `url:"PAYMENTSTATUS"`
+	PendingReason      string `url:"PENDINGREASON"`
+	ReasonCode         string `url:"REASONCODE"`
+	MsgSubID           string `url:"MSGSUBID"`
+
+	Body url.Values `url:"-"`
+}
+
+// BillAgreementUpdateReq updates or cancels a billing agreement, empty fields are not changed.
+// https://developer.paypal.com/api/nvp-soap/ba-update-nvp/#request-fields
+type BillAgreementUpdateReq struct {
+	ReferenceID string `url:"REFERENCEID"`
+	Status      string `url:"BILLINGAGREEMENTSTATUS,omitempty"`
+	Description string `url:"BILLINGAGREEMENTDESCRIPTION,omitempty"`
+	Custom      string `url:"BILLINGAGREEMENTCUSTOM,omitempty"`
+}
+
+// BillAgreementUpdateResp is the billing agreement and its payer.
+type BillAgreementUpdateResp struct {
+	NVPResp
+	BillingAgreementID string `url:"BILLINGAGREEMENTID"`
+	Status             string `url:"BILLINGAGREEMENTSTATUS"`
+	Description        string `url:"BILLINGAGREEMENTDESCRIPTION"`
+	Custom             string `url:"BILLINGAGREEMENTCUSTOM"`
+	MaxAmount          string `url:"BILLINGAGREEMENTMAX"`
+
+	Email       string `url:"EMAIL"`
+	PayerID     string `url:"PAYERID"`
+	PayerStatus string `url:"PAYERSTATUS"`
+	FirstName   string `url:"FIRSTNAME"`
+	LastName    string `url:"LASTNAME"`
+	CountryCode string `url:"COUNTRYCODE"`
+
+	Body url.Values `url:"-"`
+}
+
+// CreateBillingAgreement https://developer.paypal.com/api/nvp-soap/create-billing-agreement-nvp/
+func (c *Client) CreateBillingAgreement(ctx context.Context, req CreateBillingAgreementReq) (resp CreateBillingAgreementResp, err error) {
+	form := url.Values{}
+	if err = nvpEncoder.Encode(req, form); err != nil {
+		return resp, err
+	}
+
+	resp.Body, err = c.nvpDo(ctx, CreateBillingAgreement, form, &resp)
+	return resp, err
+}
+
+// DoReferenceTransaction https://developer.paypal.com/api/nvp-soap/do-reference-transaction-nvp/
+func (c *Client) DoReferenceTransaction(ctx context.Context, req DoReferenceTransactionReq) (resp DoReferenceTransactionResp, err error) {
+	form := url.Values{}
+	if err = nvpEncoder.Encode(req, form); err != nil {
+		return resp, err
+	}
+	for n, item := range req.Items {
+		if err = encodeIndexed(form, nvpListPrefix, strconv.Itoa(n), item); err != nil {
+			return resp, err
+		}
+	}
+
+	resp.Body, err = c.nvpDo(ctx, DoReferenceTransaction, form, &resp)
+	return resp, err
+}
+
+// BAUpdate gets the billing agreement of referenceID, it is BillAgreementUpdate without changes.
+func (c *Client) BAUpdate(ctx context.Context, referenceID string) (resp BillAgreementUpdateResp, err error) {
+	return c.BillAgreementUpdate(ctx, BillAgreementUpdateReq{ReferenceID: referenceID})
+}
+
+// BillAgreementUpdate https://developer.paypal.com/api/nvp-soap/ba-update-nvp/
+// Set Status to BillingAgreementStatusCanceled to cancel the billing agreement.
+func (c *Client) BillAgreementUpdate(ctx context.Context, req BillAgreementUpdateReq) (resp BillAgreementUpdateResp, err error) {
+	form := url.Values{}
+	if err = nvpEncoder.Encode(req, form); err != nil {
+		return resp, err
+	}
+
+	resp.Body, err = c.nvpDo(ctx, BillAgreementUpdate, form, &resp)
+	return resp, err
+}
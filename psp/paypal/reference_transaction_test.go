@@ -0,0 +1,82 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestClient_ReferenceTransaction(t *testing.T) {
+	c, s := newNVPTestClient(t, map[string]url.Values{
+		string(CreateBillingAgreement): {"ACK": {AckSuccess}, "BILLINGAGREEMENTID": {"B-5YM95264NC0392934"}},
+		string(DoReferenceTransaction): {
+			"ACK": {AckSuccess}, "BILLINGAGREEMENTID": {"B-5YM95264NC0392934"}, "TRANSACTIONID": {"9FC62427SL4418503"},
+			"PAYMENTSTATUS": {"Completed"}, "AMT": {"12.00"}, "FEEAMT": {"0.65"}, "CURRENCYCODE": {"EUR"}, "MSGSUBID": {"renew-2021-12"},
+		},
+		string(BillAgreementUpdate): {
+			"ACK": {AckSuccess}, "BILLINGAGREEMENTID": {"B-5YM95264NC0392934"}, "BILLINGAGREEMENTSTATUS": {BillingAgreementStatusActive},
+			"BILLINGAGREEMENTDESCRIPTION": {"Monthly plan"}, "PAYERID": {"QKXQ7D7QCGYPW"}, "EMAIL": {"payer@example.com"},
+		},
+	})
+	ctx := context.Background()
+
+	agreement, err := c.CreateBillingAgreement(ctx, CreateBillingAgreementReq{Token: "EC-7JY19224GB5421932"})
+	if err != nil || agreement.BillingAgreementID != "B-5YM95264NC0392934" {
+		t.Fatalf("CreateBillingAgreement() got = %+v, err = %v", agreement, err)
+	}
+	if got := s.forms[0].Get("TOKEN"); got != "EC-7JY19224GB5421932" {
+		t.Errorf("CreateBillingAgreement() TOKEN = %v", got)
+	}
+
+	transaction, err := c.DoReferenceTransaction(ctx, DoReferenceTransactionReq{
+		ReferenceID:   agreement.BillingAgreementID,
+		PaymentAction: PaymentActionSale,
+		Amount:        "12.00",
+		CurrencyCode:  "EUR",
+		MsgSubID:      "renew-2021-12",
+		Items:         []PaymentItem{{Name: "Plan", Amount: "12.00", Quantity: "1"}},
+	})
+	if err != nil || transaction.TransactionID != "9FC62427SL4418503" || transaction.PaymentStatus != "Completed" || transaction.FeeAmount != "0.65" {
+		t.Fatalf("DoReferenceTransaction() got = %+v, err = %v", transaction, err)
+	}
+	form := s.forms[1]
+	want := map[string]string{
+		"METHOD": string(DoReferenceTransaction), "REFERENCEID": "B-5YM95264NC0392934", "PAYMENTACTION": "Sale",
+		"AMT": "12.00", "MSGSUBID": "renew-2021-12", "L_NAME0": "Plan", "L_AMT0": "12.00", "L_QTY0": "1",
+	}
+	for key, value := range want {
+		if got := form[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("DoReferenceTransaction() %s = %v, want %v", key, got, value)
+		}
+	}
+
+	details, err := c.BAUpdate(ctx, "B-5YM95264NC0392934")
+	if err != nil || details.Status != BillingAgreementStatusActive || details.PayerID != "QKXQ7D7QCGYPW" {
+		t.Fatalf("BAUpdate() got = %+v, err = %v", details, err)
+	}
+	if _, ok := s.forms[2]["BILLINGAGREEMENTSTATUS"]; ok {
+		t.Errorf("BAUpdate() changes BILLINGAGREEMENTSTATUS: %v", s.forms[2])
+	}
+
+	if _, err = c.BillAgreementUpdate(ctx, BillAgreementUpdateReq{ReferenceID: "B-5YM95264NC0392934", Status: BillingAgreementStatusCanceled}); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.forms[3].Get("BILLINGAGREEMENTSTATUS"); got != BillingAgreementStatusCanceled {
+		t.Errorf("BillAgreementUpdate() BILLINGAGREEMENTSTATUS = %v", got)
+	}
+}
+
+func TestClient_DoReferenceTransactionCanceled(t *testing.T) {
+	c, _ := newNVPTestClient(t, map[string]url.Values{
+		string(DoReferenceTransaction): {
+			"ACK": {AckFailure}, "L_ERRORCODE0": {"10201"}, "L_SHORTMESSAGE0": {"Agreement canceled"},
+			"L_LONGMESSAGE0": {"Billing Agreement was cancelled"},
+		},
+	})
+
+	_, err := c.DoReferenceTransaction(context.Background(), DoReferenceTransactionReq{ReferenceID: "B-5YM95264NC0392934", Amount: "12.00"})
+	if !errors.Is(err, ErrBillingAgreementCanceled) {
+		t.Errorf("DoReferenceTransaction() err = %v, want %v", err, ErrBillingAgreementCanceled)
+	}
+}
@@ -0,0 +1,378 @@
+// Package report parses the settlement (STL) and transaction detail (TRR) reports paypal delivers by SFTP.
+// https://developer.paypal.com/docs/reports/sftp-reports/settlement-report/
+// https://developer.paypal.com/docs/reports/sftp-reports/transaction-detail/
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Type string
+
+const (
+	TypeSettlement        Type = "STL"
+	TypeTransactionDetail Type = "TRR"
+)
+
+// record types in the first column of the rows
+const (
+	recordReportHeader  = "RH"
+	recordFileHeader    = "FH"
+	recordSectionHeader = "SH"
+	recordColumnHeader  = "CH"
+	recordSectionBody   = "SB"
+	recordSectionFooter = "SF"
+	recordSectionCount  = "SC"
+	recordReportFooter  = "RF"
+	recordReportCount   = "RC"
+	recordFileFooter    = "FF"
+)
+
+// values of the debit or credit columns
+const (
+	Credit = "CR"
+	Debit  = "DR"
+)
+
+const timeLayout = "2006/01/02 15:04:05 -0700"
+
+// column names of the CH record, compared case insensitive
+const (
+	columnTransactionID     = "transaction id"
+	columnInvoiceID         = "invoice id"
+	columnReferenceID       = "paypal reference id"
+	columnReferenceIDType   = "paypal reference id type"
+	columnEventCode         = "transaction event code"
+	columnInitiationDate    = "transaction initiation date"
+	columnCompletionDate    = "transaction completion date"
+	columnDebitOrCredit     = "transaction debit or credit"
+	columnGrossAmount       = "gross transaction amount"
+	columnGrossCurrency     = "gross transaction currency"
+	columnFeeDebitOrCredit  = "fee debit or credit"
+	columnFeeAmount         = "fee amount"
+	columnFeeCurrency       = "fee currency"
+	columnCustomField       = "custom field"
+	columnTransactionStatus = "transactional status"
+)
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// Header is the RH record of a report.
+type Header struct {
+	GenerationDate  time.Time
+	ReportingWindow string
+	AccountID       string
+	Version         string
+}
+
+// Row is a SB record of a report, the amounts are in the lower denomination of the currency, e.g. 1005 for $10.05.
+type Row struct {
+	TransactionID     string
+	InvoiceID         string
+	ReferenceID       string
+	ReferenceIDType   string
+	EventCode         string
+	InitiationDate    time.Time
+	CompletionDate    time.Time
+	DebitOrCredit     string
+	GrossAmount       int64
+	GrossCurrency     string
+	FeeDebitOrCredit  string
+	FeeAmount         int64
+	FeeCurrency       string
+	CustomField       string
+	TransactionStatus string
+
+	// Fields are all the columns of the row by the name in the CH record.
+	Fields map[string]string
+}
+
+// GetInvoiceID returns the invoice id, or the custom field if the invoice id is empty, like paypal.TransactionInfo.GetInvoiceID.
+func (r Row) GetInvoiceID() string {
+	if r.InvoiceID != "" {
+		return r.InvoiceID
+	}
+	return r.CustomField
+}
+
+// Gross returns the gross amount, negative if it is debited.
+func (r Row) Gross() int64 {
+	if r.DebitOrCredit == Debit {
+		return -r.GrossAmount
+	}
+	return r.GrossAmount
+}
+
+// Fee returns the fee, negative if it is charged.
+func (r Row) Fee() int64 {
+	if r.FeeDebitOrCredit == Debit {
+		return -r.FeeAmount
+	}
+	return r.FeeAmount
+}
+
+// Report is a parsed STL or TRR file.
+type Report struct {
+	Type        Type
+	Header      Header
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Rows        []Row
+}
+
+// Summary is the total of the rows in a currency, the amounts are in the lower denomination.
+type Summary struct {
+	Currency string
+	Count    int
+	Credits  int64
+	Debits   int64
+	// Fees are the fees charged minus the fees refunded.
+	Fees int64
+	Net  int64
+}
+
+// Summaries returns the totals of the rows by currency, sorted by currency.
+func (r *Report) Summaries() []Summary {
+	byCurrency := make(map[string]*Summary)
+	summaryOf := func(currency string) *Summary {
+		s, ok := byCurrency[currency]
+		if !ok {
+			s = &Summary{Currency: currency}
+			byCurrency[currency] = s
+		}
+		return s
+	}
+
+	for _, row := range r.Rows {
+		s := summaryOf(row.GrossCurrency)
+		s.Count++
+		if row.DebitOrCredit == Debit {
+			s.Debits += row.GrossAmount
+		} else {
+			s.Credits += row.GrossAmount
+		}
+		s.Net += row.Gross()
+
+		if row.FeeAmount != 0 {
+			s = summaryOf(row.FeeCurrency)
+			s.Fees -= row.Fee()
+			s.Net += row.Fee()
+		}
+	}
+
+	summaries := make([]Summary, 0, len(byCurrency))
+	for _, s := range byCurrency {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Currency < summaries[j].Currency })
+	return summaries
+}
+
+// RowsByInvoiceID groups the rows by GetInvoiceID to match them against the orders, rows without one are left out.
+func (r *Report) RowsByInvoiceID() map[string][]Row {
+	rows := make(map[string][]Row, len(r.Rows))
+	for _, row := range r.Rows {
+		if invoiceID := row.GetInvoiceID(); invoiceID != "" {
+			rows[invoiceID] = append(rows[invoiceID], row)
+		}
+	}
+	return rows
+}
+
+// ParseFile parses a report file, such as STL-20210101.01.008.CSV.
+func ParseFile(name string) (*Report, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse parses a STL or TRR report, the row counts of the SC and RC records are checked if present.
+func Parse(r io.Reader) (*Report, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	report := &Report{}
+	var columns []string
+	var sectionRows int
+	var sectionStarted, reportStarted bool
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 {
+			continue
+		}
+
+		switch strings.TrimSpace(record[0]) {
+		case recordReportHeader:
+			reportStarted = true
+			report.Header = Header{ReportingWindow: field(record, 2), AccountID: field(record, 3), Version: field(record, 4)}
+			if report.Header.GenerationDate, err = parseTime(field(record, 1)); err != nil {
+				return nil, fmt.Errorf("report: line %d: %w", line, err)
+			}
+		case recordSectionHeader:
+			sectionStarted, sectionRows = true, 0
+			start, err := parseTime(field(record, 1))
+			if err != nil {
+				return nil, fmt.Errorf("report: line %d: %w", line, err)
+			}
+			end, err := parseTime(field(record, 2))
+			if err != nil {
+				return nil, fmt.Errorf("report: line %d: %w", line, err)
+			}
+			if report.PeriodStart.IsZero() || start.Before(report.PeriodStart) {
+				report.PeriodStart = start
+			}
+			if end.After(report.PeriodEnd) {
+				report.PeriodEnd = end
+			}
+		case recordColumnHeader:
+			columns = make([]string, len(record))
+			for i, name := range record {
+				columns[i] = strings.TrimSpace(name)
+			}
+			report.Type = typeOf(columns)
+		case recordSectionBody:
+			if columns == nil {
+				return nil, fmt.Errorf("report: line %d: SB record before CH record", line)
+			}
+			row, err := parseRow(columns, record)
+			if err != nil {
+				return nil, fmt.Errorf("report: line %d: %w", line, err)
+			}
+			report.Rows = append(report.Rows, row)
+			sectionRows++
+		case recordSectionCount:
+			if err = checkCount(sectionStarted, field(record, 1), sectionRows); err != nil {
+				return nil, fmt.Errorf("report: line %d: section %w", line, err)
+			}
+		case recordReportCount:
+			if err = checkCount(reportStarted, field(record, 1), len(report.Rows)); err != nil {
+				return nil, fmt.Errorf("report: line %d: report %w", line, err)
+			}
+		case recordFileHeader, recordSectionFooter, recordReportFooter, recordFileFooter:
+		default:
+			return nil, fmt.Errorf("report: line %d: unknown record type %q", line, record[0])
+		}
+	}
+
+	if columns == nil {
+		return nil, errors.New("report: no CH record")
+	}
+	return report, nil
+}
+
+// checkCount compares the rows with the count record, a split report may carry the count of the rows in another file.
+func checkCount(started bool, count string, rows int) error {
+	if !started {
+		return nil
+	}
+
+	want, err := strconv.Atoi(count)
+	if err != nil {
+		return fmt.Errorf("count %q: %w", count, err)
+	}
+	if want != rows {
+		return fmt.Errorf("has %d rows, want %d", rows, want)
+	}
+	return nil
+}
+
+func parseRow(columns, record []string) (Row, error) {
+	fields := make(map[string]string, len(columns))
+	byName := make(map[string]string, len(columns))
+	for i := 1; i < len(columns) && i < len(record); i++ {
+		fields[columns[i]] = record[i]
+		byName[normalize(columns[i])] = record[i]
+	}
+
+	row := Row{
+		TransactionID:     byName[columnTransactionID],
+		InvoiceID:         byName[columnInvoiceID],
+		ReferenceID:       byName[columnReferenceID],
+		ReferenceIDType:   byName[columnReferenceIDType],
+		EventCode:         byName[columnEventCode],
+		DebitOrCredit:     byName[columnDebitOrCredit],
+		GrossCurrency:     byName[columnGrossCurrency],
+		FeeDebitOrCredit:  byName[columnFeeDebitOrCredit],
+		FeeCurrency:       byName[columnFeeCurrency],
+		CustomField:       byName[columnCustomField],
+		TransactionStatus: byName[columnTransactionStatus],
+		Fields:            fields,
+	}
+
+	var err error
+	if row.InitiationDate, err = parseTime(byName[columnInitiationDate]); err != nil {
+		return row, err
+	}
+	if row.CompletionDate, err = parseTime(byName[columnCompletionDate]); err != nil {
+		return row, err
+	}
+	if row.GrossAmount, err = parseAmount(byName[columnGrossAmount]); err != nil {
+		return row, err
+	}
+	if row.FeeAmount, err = parseAmount(byName[columnFeeAmount]); err != nil {
+		return row, err
+	}
+	return row, nil
+}
+
+func typeOf(columns []string) Type {
+	for _, name := range columns {
+		if normalize(name) == columnTransactionStatus {
+			return TypeTransactionDetail
+		}
+	}
+	return TypeSettlement
+}
+
+// normalize lowers the column name and collapses the spaces, some reports have two spaces in a name.
+func normalize(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func field(record []string, i int) string {
+	if i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(timeLayout, s)
+}
+
+func parseAmount(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const settlementReport = "\xef\xbb\xbf" + `"RH","2021/01/02 03:04:05 -0800","A","MERCHANT1","008"
+"FH",01
+"SH","2021/01/01 00:00:00 -0800","2021/01/01 23:59:59 -0800","MERCHANT1",""
+"CH","Transaction ID","Invoice ID","PayPal Reference ID","PayPal Reference ID Type","Transaction Event Code","Transaction Initiation Date","Transaction Completion Date","Transaction  Debit or Credit","Gross Transaction Amount","Gross Transaction Currency","Fee Debit or Credit","Fee Amount","Fee Currency","Custom Field","Consumer ID"
+"SB","5TY05013RG002845M","INV-1","","","T0006","2021/01/01 10:00:00 -0800","2021/01/01 10:00:05 -0800","CR","1005","USD","DR","59","USD","","buyer@example.com"
+"SB","8UK57712DB8306034","","5TY05013RG002845M","TXN","T1107","2021/01/01 12:00:00 -0800","2021/01/01 12:00:00 -0800","DR","500","USD","CR","15","USD","INV-1",""
+"SB","1AB23456CD7890123","INV-2","","","T0006","2021/01/01 13:00:00 -0800","2021/01/01 13:00:00 -0800","CR","2000","EUR","DR","70","EUR","",""
+"SF","USD","CR","1005"
+"SC","3"
+"RF","3"
+"RC","3"
+"FF","1"
+`
+
+func TestParse(t *testing.T) {
+	report, err := Parse(strings.NewReader(settlementReport))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Type != TypeSettlement || report.Header.AccountID != "MERCHANT1" || report.Header.Version != "008" ||
+		report.Header.GenerationDate.Unix() != time.Date(2021, 1, 2, 11, 4, 5, 0, time.UTC).Unix() {
+		t.Errorf("Parse() header = %+v, type = %v", report.Header, report.Type)
+	}
+	if report.PeriodStart.Day() != 1 || report.PeriodEnd.Hour() != 23 {
+		t.Errorf("Parse() period = %v - %v", report.PeriodStart, report.PeriodEnd)
+	}
+	if len(report.Rows) != 3 {
+		t.Fatalf("Parse() rows = %d, want 3", len(report.Rows))
+	}
+
+	refund := report.Rows[1]
+	if refund.TransactionID != "8UK57712DB8306034" || refund.ReferenceID != "5TY05013RG002845M" || refund.Gross() != -500 ||
+		refund.Fee() != 15 || refund.GetInvoiceID() != "INV-1" || refund.Fields["Consumer ID"] != "" {
+		t.Errorf("Parse() row = %+v", refund)
+	}
+	if got := report.Rows[0].Fields["Consumer ID"]; got != "buyer@example.com" {
+		t.Errorf("Parse() Consumer ID = %v", got)
+	}
+
+	want := []Summary{
+		{Currency: "EUR", Count: 1, Credits: 2000, Fees: 70, Net: 1930},
+		{Currency: "USD", Count: 2, Credits: 1005, Debits: 500, Fees: 44, Net: 461},
+	}
+	summaries := report.Summaries()
+	if len(summaries) != len(want) {
+		t.Fatalf("Summaries() = %+v, want %+v", summaries, want)
+	}
+	for i := range want {
+		if summaries[i] != want[i] {
+			t.Errorf("Summaries()[%d] = %+v, want %+v", i, summaries[i], want[i])
+		}
+	}
+
+	byInvoice := report.RowsByInvoiceID()
+	if len(byInvoice["INV-1"]) != 2 || len(byInvoice["INV-2"]) != 1 {
+		t.Errorf("RowsByInvoiceID() = %+v", byInvoice)
+	}
+}
+
+func TestParseTransactionDetail(t *testing.T) {
+	content := `"RH","2021/01/02 03:04:05 -0800","A","MERCHANT1","011"
+"SH","2021/01/01 00:00:00 -0800","2021/01/01 23:59:59 -0800","MERCHANT1",""
+"CH","Transaction ID","Invoice ID","Transaction Debit or Credit","Gross Transaction Amount","Gross Transaction Currency","Transactional Status","Item Name"
+"SB","5TY05013RG002845M","INV-1","CR","1005","USD","S","Pass"
+"SC","1"
+`
+	name := filepath.Join(t.TempDir(), "TRR-20210101.01.011.CSV")
+	if err := ioutil.WriteFile(name, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := ParseFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Type != TypeTransactionDetail || report.Rows[0].TransactionStatus != "S" || report.Rows[0].Fields["Item Name"] != "Pass" {
+		t.Errorf("ParseFile() = %+v", report)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "section count", content: strings.Replace(settlementReport, `"SC","3"`, `"SC","4"`, 1), want: "section has 3 rows, want 4"},
+		{name: "report count", content: strings.Replace(settlementReport, `"RC","3"`, `"RC","2"`, 1), want: "report has 3 rows, want 2"},
+		{name: "amount", content: strings.Replace(settlementReport, `"1005"`, `"10.05"`, 1), want: "line 5"},
+		{name: "body before columns", content: `"SB","5TY05013RG002845M"`, want: "SB record before CH record"},
+		{name: "unknown record", content: `"XX","1"`, want: "unknown record type"},
+		{name: "empty", content: "", want: "no CH record"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.content)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
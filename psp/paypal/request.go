@@ -0,0 +1,95 @@
+package paypal
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/dghubble/sling"
+	"github.com/linhoi/kit/log"
+)
+
+const headerDebugID = "PayPal-Debug-Id"
+
+// request is a REST call of paypal, it is built on a fresh copy of the client sling,
+// so headers and query params never leak between concurrent calls or pages.
+type request struct {
+	method string
+	// path is relative to Config.Host, or an absolute url such as a HATEOAS next link.
+	path  string
+	query interface{}
+	body  interface{}
+	// upload is sent instead of body when the request is not json, such as a multipart form with files.
+	upload sling.BodyProvider
+	// requestID is sent as PayPal-Request-Id to make POST requests idempotent.
+	requestID string
+	// prefer asks paypal to return the full resource instead of the minimal one.
+	prefer bool
+}
+
+// do sends the request with the access token and decodes the response into result,
+// a failed response is returned as *APIError.
+func (c *Client) do(ctx context.Context, name string, r request, result interface{}) error {
+	accessToken, err := c.GetAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newRequest(r, accessToken).Request()
+	if err != nil {
+		return err
+	}
+
+	var apiErr APIError
+	resp, err := c.client.Do(req.WithContext(ctx), result, &apiErr)
+	if err == nil && resp.StatusCode >= http.StatusMultipleChoices {
+		err = newAPIError(resp, apiErr)
+	}
+	if err != nil {
+		log.S(ctx).Errorw(name, "path", r.path, "err", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) newRequest(r request, accessToken string) *sling.Sling {
+	path := r.path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		path = c.config.Host + path
+	}
+
+	s := c.client.New().Set("Authorization", "Bearer "+accessToken).
+		Set("Accept", "application/json")
+	if r.requestID != "" {
+		s = s.Set(headerRequestID, r.requestID)
+	}
+	if r.prefer {
+		s = s.Set(headerPrefer, preferFull)
+	}
+	if r.query != nil {
+		s = s.QueryStruct(r.query)
+	}
+	if r.upload != nil {
+		s = s.BodyProvider(r.upload)
+	} else if r.body != nil {
+		s = s.BodyJSON(r.body)
+	} else if r.method == http.MethodPost {
+		// paypal rejects POST requests without a json body
+		s = s.BodyJSON(struct{}{})
+	}
+
+	switch r.method {
+	case http.MethodPost:
+		return s.Post(path)
+	case http.MethodPut:
+		return s.Put(path)
+	case http.MethodPatch:
+		return s.Patch(path)
+	case http.MethodDelete:
+		return s.Delete(path)
+	default:
+		return s.Get(path)
+	}
+}
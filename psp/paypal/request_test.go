@@ -0,0 +1,109 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// echoServer echoes the query and headers of a request, and fails the test if a request carries repeated values.
+func echoServer(t *testing.T) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == getAccessTokenPath {
+			_, _ = w.Write([]byte(`{"access_token":"A21AA-test","expires_in":32400}`))
+			return
+		}
+
+		for key, values := range r.URL.Query() {
+			if len(values) > 1 {
+				t.Errorf("%s %s query %s repeated: %v", r.Method, r.URL.Path, key, values)
+			}
+		}
+		for _, key := range []string{"Authorization", headerRequestID} {
+			if values := r.Header.Values(key); len(values) > 1 {
+				t.Errorf("%s %s header %s repeated: %v", r.Method, r.URL.Path, key, values)
+			}
+		}
+
+		switch {
+		case r.URL.Path == getTransactionPath:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"transaction_details": []map[string]interface{}{
+					{"transaction_info": map[string]string{"transaction_id": r.URL.Query().Get("transaction_id")}},
+				},
+			})
+		case r.URL.Path == listDisputesPath:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"items": []map[string]string{{"dispute_id": "PP-" + r.URL.Query().Get("next_page_token")}},
+			})
+		case strings.HasPrefix(r.URL.Path, ordersPath):
+			_ = json.NewEncoder(w).Encode(map[string]string{"id": r.Header.Get(headerRequestID)})
+		default:
+			w.Header().Set(headerDebugID, "f2a8d7e1c3b4")
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{Host: server.URL, ClientID: "client", Secret: "secret"})
+}
+
+func TestClient_ConcurrentRequests(t *testing.T) {
+	c := echoServer(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			transactionID := fmt.Sprintf("TX%d", i)
+			transaction, err := c.GetTransaction(ctx, transactionID, time.Now().Add(-time.Hour), time.Now())
+			if err != nil || transaction.TransactionId != transactionID {
+				t.Errorf("GetTransaction() got = %v, err = %v, want %v", transaction.TransactionId, err, transactionID)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			token := fmt.Sprintf("page%d", i)
+			items, _, err := c.ListOnePageDisputes(ctx, token)
+			if err != nil || len(items) != 1 || items[0].DisputeID != "PP-"+token {
+				t.Errorf("ListOnePageDisputes() got = %v, err = %v, want PP-%v", items, err, token)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			requestID := ""
+			if i%2 == 0 {
+				requestID = fmt.Sprintf("order-%d", i)
+			}
+			order, err := c.CreateOrder(ctx, CreateOrderReq{RequestID: requestID, Intent: OrderIntentCapture})
+			if err != nil || order.ID != requestID {
+				t.Errorf("CreateOrder() PayPal-Request-Id = %v, err = %v, want %v", order.ID, err, requestID)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// the first page after other pages carries no page token
+	items, _, err := c.ListOnePageDisputes(ctx, "")
+	if err != nil || len(items) != 1 || items[0].DisputeID != "PP-" {
+		t.Errorf("ListOnePageDisputes() got = %v, err = %v", items, err)
+	}
+}
+
+func TestClient_DebugID(t *testing.T) {
+	c := echoServer(t)
+
+	_, err := c.GetCapture(context.Background(), "2GG279541U471931P")
+	if err == nil || !strings.Contains(err.Error(), "f2a8d7e1c3b4") {
+		t.Errorf("GetCapture() err = %v, want debug id", err)
+	}
+}
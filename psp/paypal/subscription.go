@@ -0,0 +1,408 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	plansPath         = "/v1/billing/plans"         // https://developer.paypal.com/docs/api/subscriptions/v1/#plans
+	subscriptionsPath = "/v1/billing/subscriptions" // https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions
+)
+
+type PlanStatus string
+
+const (
+	PlanStatusCreated  PlanStatus = "CREATED"
+	PlanStatusInactive PlanStatus = "INACTIVE"
+	PlanStatusActive   PlanStatus = "ACTIVE"
+)
+
+type IntervalUnit string
+
+const (
+	IntervalUnitDay   IntervalUnit = "DAY"
+	IntervalUnitWeek  IntervalUnit = "WEEK"
+	IntervalUnitMonth IntervalUnit = "MONTH"
+	IntervalUnitYear  IntervalUnit = "YEAR"
+)
+
+type TenureType string
+
+const (
+	TenureTypeRegular TenureType = "REGULAR"
+	TenureTypeTrial   TenureType = "TRIAL"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusApprovalPending SubscriptionStatus = "APPROVAL_PENDING"
+	SubscriptionStatusApproved        SubscriptionStatus = "APPROVED"
+	SubscriptionStatusActive          SubscriptionStatus = "ACTIVE"
+	SubscriptionStatusSuspended       SubscriptionStatus = "SUSPENDED"
+	SubscriptionStatusCancelled       SubscriptionStatus = "CANCELLED"
+	SubscriptionStatusExpired         SubscriptionStatus = "EXPIRED"
+)
+
+type Frequency struct {
+	IntervalUnit  IntervalUnit `json:"interval_unit"`
+	IntervalCount int          `json:"interval_count,omitempty"`
+}
+
+type PricingScheme struct {
+	FixedPrice *Money     `json:"fixed_price,omitempty"`
+	Version    int        `json:"version,omitempty"`
+	CreateTime *time.Time `json:"create_time,omitempty"`
+	UpdateTime *time.Time `json:"update_time,omitempty"`
+}
+
+// BillingCycle https://developer.paypal.com/docs/api/subscriptions/v1/#definition-billing_cycle
+type BillingCycle struct {
+	Frequency     Frequency      `json:"frequency"`
+	TenureType    TenureType     `json:"tenure_type"`
+	Sequence      int            `json:"sequence"`
+	TotalCycles   int            `json:"total_cycles"`
+	PricingScheme *PricingScheme `json:"pricing_scheme,omitempty"`
+}
+
+// PaymentPreferences https://developer.paypal.com/docs/api/subscriptions/v1/#definition-payment_preferences
+type PaymentPreferences struct {
+	AutoBillOutstanding bool   `json:"auto_bill_outstanding"`
+	SetupFee            *Money `json:"setup_fee,omitempty"`
+	// SetupFeeFailureAction is CONTINUE or CANCEL.
+	SetupFeeFailureAction   string `json:"setup_fee_failure_action,omitempty"`
+	PaymentFailureThreshold int    `json:"payment_failure_threshold,omitempty"`
+}
+
+type Taxes struct {
+	Percentage string `json:"percentage"`
+	Inclusive  bool   `json:"inclusive"`
+}
+
+// Plan https://developer.paypal.com/docs/api/subscriptions/v1/#plans_get
+type Plan struct {
+	ID                 string              `json:"id"`
+	ProductID          string              `json:"product_id"`
+	Name               string              `json:"name"`
+	Description        string              `json:"description,omitempty"`
+	Status             PlanStatus          `json:"status"`
+	BillingCycles      []BillingCycle      `json:"billing_cycles,omitempty"`
+	PaymentPreferences *PaymentPreferences `json:"payment_preferences,omitempty"`
+	Taxes              *Taxes              `json:"taxes,omitempty"`
+	QuantitySupported  bool                `json:"quantity_supported"`
+	CreateTime         time.Time           `json:"create_time"`
+	UpdateTime         time.Time           `json:"update_time"`
+	Links              []HATEOASLink       `json:"links,omitempty"`
+}
+
+type CreatePlanReq struct {
+	// RequestID is sent as PayPal-Request-Id, requests with the same id create the plan only once.
+	RequestID   string `json:"-"`
+	ProductID   string `json:"product_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Status is ACTIVE by default, a CREATED plan must be activated before subscribing.
+	Status             PlanStatus          `json:"status,omitempty"`
+	BillingCycles      []BillingCycle      `json:"billing_cycles"`
+	PaymentPreferences *PaymentPreferences `json:"payment_preferences"`
+	Taxes              *Taxes              `json:"taxes,omitempty"`
+	QuantitySupported  bool                `json:"quantity_supported,omitempty"`
+}
+
+type ListPlansReq struct {
+	ListReq
+	ProductID string `url:"product_id,omitempty"`
+	// PlanIDs is a comma separated list of plan ids.
+	PlanIDs string `url:"plan_ids,omitempty"`
+}
+
+type ListPlansResp struct {
+	Plans      []Plan        `json:"plans"`
+	TotalItems int           `json:"total_items"`
+	TotalPages int           `json:"total_pages"`
+	Links      []HATEOASLink `json:"links"`
+}
+
+// PricingSchemeUpdate changes the price of the billing cycle of Sequence.
+type PricingSchemeUpdate struct {
+	BillingCycleSequence int           `json:"billing_cycle_sequence"`
+	PricingScheme        PricingScheme `json:"pricing_scheme"`
+}
+
+type updatePricingSchemesReq struct {
+	PricingSchemes []PricingSchemeUpdate `json:"pricing_schemes"`
+}
+
+type Subscriber struct {
+	Name            *Name           `json:"name,omitempty"`
+	EmailAddress    string          `json:"email_address,omitempty"`
+	PayerID         string          `json:"payer_id,omitempty"`
+	ShippingAddress *ShippingDetail `json:"shipping_address,omitempty"`
+}
+
+type CycleExecution struct {
+	TenureType      TenureType `json:"tenure_type"`
+	Sequence        int        `json:"sequence"`
+	CyclesCompleted int        `json:"cycles_completed"`
+	CyclesRemaining int        `json:"cycles_remaining"`
+	TotalCycles     int        `json:"total_cycles"`
+}
+
+type LastPayment struct {
+	Amount *Money    `json:"amount,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// SubscriptionBillingInfo https://developer.paypal.com/docs/api/subscriptions/v1/#definition-subscription_billing_info
+type SubscriptionBillingInfo struct {
+	OutstandingBalance  *Money           `json:"outstanding_balance,omitempty"`
+	CycleExecutions     []CycleExecution `json:"cycle_executions,omitempty"`
+	LastPayment         *LastPayment     `json:"last_payment,omitempty"`
+	NextBillingTime     *time.Time       `json:"next_billing_time,omitempty"`
+	FinalPaymentTime    *time.Time       `json:"final_payment_time,omitempty"`
+	FailedPaymentsCount int              `json:"failed_payments_count"`
+}
+
+// Subscription https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_get
+type Subscription struct {
+	ID               string                   `json:"id"`
+	PlanID           string                   `json:"plan_id"`
+	Status           SubscriptionStatus       `json:"status"`
+	StatusChangeNote string                   `json:"status_change_note,omitempty"`
+	StatusUpdateTime *time.Time               `json:"status_update_time,omitempty"`
+	StartTime        *time.Time               `json:"start_time,omitempty"`
+	Quantity         string                   `json:"quantity,omitempty"`
+	ShippingAmount   *Money                   `json:"shipping_amount,omitempty"`
+	Subscriber       *Subscriber              `json:"subscriber,omitempty"`
+	BillingInfo      *SubscriptionBillingInfo `json:"billing_info,omitempty"`
+	CustomID         string                   `json:"custom_id,omitempty"`
+	PlanOverridden   bool                     `json:"plan_overridden"`
+	CreateTime       time.Time                `json:"create_time"`
+	UpdateTime       time.Time                `json:"update_time"`
+	Links            []HATEOASLink            `json:"links,omitempty"`
+}
+
+// ApproveURL returns the url the subscriber should be redirected to.
+func (s Subscription) ApproveURL() string {
+	return approveURL(s.Links)
+}
+
+type CreateSubscriptionReq struct {
+	// RequestID is sent as PayPal-Request-Id, requests with the same id create the subscription only once.
+	RequestID          string              `json:"-"`
+	PlanID             string              `json:"plan_id"`
+	StartTime          *time.Time          `json:"start_time,omitempty"`
+	Quantity           string              `json:"quantity,omitempty"`
+	ShippingAmount     *Money              `json:"shipping_amount,omitempty"`
+	Subscriber         *Subscriber         `json:"subscriber,omitempty"`
+	CustomID           string              `json:"custom_id,omitempty"`
+	ApplicationContext *ApplicationContext `json:"application_context,omitempty"`
+}
+
+// ReviseSubscriptionReq changes the plan or quantity of a subscription, the subscriber approves it again if required.
+type ReviseSubscriptionReq struct {
+	PlanID             string              `json:"plan_id,omitempty"`
+	Quantity           string              `json:"quantity,omitempty"`
+	ShippingAmount     *Money              `json:"shipping_amount,omitempty"`
+	ShippingAddress    *ShippingDetail     `json:"shipping_address,omitempty"`
+	ApplicationContext *ApplicationContext `json:"application_context,omitempty"`
+}
+
+type ReviseSubscriptionResp struct {
+	PlanID          string          `json:"plan_id"`
+	Quantity        string          `json:"quantity,omitempty"`
+	EffectiveTime   *time.Time      `json:"effective_time,omitempty"`
+	ShippingAmount  *Money          `json:"shipping_amount,omitempty"`
+	ShippingAddress *ShippingDetail `json:"shipping_address,omitempty"`
+	PlanOverridden  bool            `json:"plan_overridden"`
+	Links           []HATEOASLink   `json:"links,omitempty"`
+}
+
+// ApproveURL returns the url the subscriber approves the revision at, or an empty string if no approval is required.
+func (r ReviseSubscriptionResp) ApproveURL() string {
+	return approveURL(r.Links)
+}
+
+type subscriptionReasonReq struct {
+	Reason string `json:"reason"`
+}
+
+// SubscriptionTransaction is a payment or refund of a subscription.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#definition-transaction
+type SubscriptionTransaction struct {
+	ID                  string `json:"id"`
+	Status              string `json:"status"`
+	AmountWithBreakdown struct {
+		GrossAmount *Money `json:"gross_amount,omitempty"`
+		FeeAmount   *Money `json:"fee_amount,omitempty"`
+		NetAmount   *Money `json:"net_amount,omitempty"`
+	} `json:"amount_with_breakdown"`
+	PayerName         *Name     `json:"payer_name,omitempty"`
+	PayerEmailAddress string    `json:"payer_email,omitempty"`
+	Time              time.Time `json:"time"`
+}
+
+type listSubscriptionTransactionsReq struct {
+	StartTime string `url:"start_time"`
+	EndTime   string `url:"end_time"`
+}
+
+type ListSubscriptionTransactionsResp struct {
+	Transactions []SubscriptionTransaction `json:"transactions"`
+	TotalItems   int                       `json:"total_items"`
+	TotalPages   int                       `json:"total_pages"`
+	Links        []HATEOASLink             `json:"links"`
+}
+
+// Sale is the resource of the PAYMENT.SALE.* events, which are the payments of subscriptions.
+// https://developer.paypal.com/docs/api-basics/notifications/webhooks/event-names/#subscriptions
+type Sale struct {
+	ID     string `json:"id"`
+	State  string `json:"state"`
+	Amount struct {
+		Total    string `json:"total"`
+		Currency string `json:"currency"`
+	} `json:"amount"`
+	TransactionFee *struct {
+		Value    string `json:"value"`
+		Currency string `json:"currency"`
+	} `json:"transaction_fee,omitempty"`
+	// BillingAgreementID is the id of the subscription.
+	BillingAgreementID string        `json:"billing_agreement_id"`
+	Custom             string        `json:"custom,omitempty"`
+	InvoiceNumber      string        `json:"invoice_number,omitempty"`
+	CreateTime         time.Time     `json:"create_time"`
+	UpdateTime         time.Time     `json:"update_time"`
+	Links              []HATEOASLink `json:"links,omitempty"`
+}
+
+// CreatePlan https://developer.paypal.com/docs/api/subscriptions/v1/#plans_create
+func (c *Client) CreatePlan(ctx context.Context, req CreatePlanReq) (plan Plan, err error) {
+	err = c.do(ctx, "CreatePlan", request{method: http.MethodPost, path: plansPath, body: req, requestID: req.RequestID, prefer: true}, &plan)
+	return plan, err
+}
+
+// ListPlans lists one page of the plans.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#plans_list
+func (c *Client) ListPlans(ctx context.Context, req ListPlansReq) (resp ListPlansResp, err error) {
+	err = c.do(ctx, "ListPlans", request{method: http.MethodGet, path: plansPath, query: req}, &resp)
+	return resp, err
+}
+
+// GetPlan https://developer.paypal.com/docs/api/subscriptions/v1/#plans_get
+func (c *Client) GetPlan(ctx context.Context, planID string) (plan Plan, err error) {
+	if planID == "" {
+		return plan, errors.New("planID is zero")
+	}
+
+	err = c.do(ctx, "GetPlan", request{method: http.MethodGet, path: plansPath + "/" + planID}, &plan)
+	return plan, err
+}
+
+// UpdatePlan updates the description, payment_preferences or taxes of a plan.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#plans_patch
+func (c *Client) UpdatePlan(ctx context.Context, planID string, operations []PatchOperation) error {
+	if planID == "" {
+		return errors.New("planID is zero")
+	}
+
+	return c.do(ctx, "UpdatePlan", request{method: http.MethodPatch, path: plansPath + "/" + planID, body: operations}, nil)
+}
+
+// ActivatePlan https://developer.paypal.com/docs/api/subscriptions/v1/#plans_activate
+func (c *Client) ActivatePlan(ctx context.Context, planID string) error {
+	return c.planAction(ctx, "ActivatePlan", planID, "/activate", nil)
+}
+
+// DeactivatePlan stops new subscriptions of a plan, the existing subscriptions are not affected.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#plans_deactivate
+func (c *Client) DeactivatePlan(ctx context.Context, planID string) error {
+	return c.planAction(ctx, "DeactivatePlan", planID, "/deactivate", nil)
+}
+
+// UpdatePlanPricing changes the prices of a plan, the existing subscriptions are charged the new prices too.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#plans_update-pricing-schemes
+func (c *Client) UpdatePlanPricing(ctx context.Context, planID string, pricingSchemes []PricingSchemeUpdate) error {
+	return c.planAction(ctx, "UpdatePlanPricing", planID, "/update-pricing-schemes", updatePricingSchemesReq{PricingSchemes: pricingSchemes})
+}
+
+func (c *Client) planAction(ctx context.Context, name, planID, action string, body interface{}) error {
+	if planID == "" {
+		return errors.New("planID is zero")
+	}
+
+	return c.do(ctx, name, request{method: http.MethodPost, path: plansPath + "/" + planID + action, body: body}, nil)
+}
+
+// CreateSubscription creates a subscription in APPROVAL_PENDING status, redirect the subscriber to ApproveURL.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_create
+func (c *Client) CreateSubscription(ctx context.Context, req CreateSubscriptionReq) (subscription Subscription, err error) {
+	err = c.do(ctx, "CreateSubscription", request{method: http.MethodPost, path: subscriptionsPath, body: req, requestID: req.RequestID, prefer: true}, &subscription)
+	return subscription, err
+}
+
+// GetSubscription https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_get
+func (c *Client) GetSubscription(ctx context.Context, subscriptionID string) (subscription Subscription, err error) {
+	if subscriptionID == "" {
+		return subscription, errors.New("subscriptionID is zero")
+	}
+
+	err = c.do(ctx, "GetSubscription", request{method: http.MethodGet, path: subscriptionsPath + "/" + subscriptionID}, &subscription)
+	return subscription, err
+}
+
+// ReviseSubscription upgrades or downgrades the plan or quantity of a subscription.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_revise
+func (c *Client) ReviseSubscription(ctx context.Context, subscriptionID string, req ReviseSubscriptionReq) (resp ReviseSubscriptionResp, err error) {
+	if subscriptionID == "" {
+		return resp, errors.New("subscriptionID is zero")
+	}
+
+	err = c.do(ctx, "ReviseSubscription", request{method: http.MethodPost, path: subscriptionsPath + "/" + subscriptionID + "/revise", body: req}, &resp)
+	return resp, err
+}
+
+// SuspendSubscription https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_suspend
+func (c *Client) SuspendSubscription(ctx context.Context, subscriptionID string, reason string) error {
+	return c.subscriptionAction(ctx, "SuspendSubscription", subscriptionID, "/suspend", reason)
+}
+
+// ActivateSubscription reactivates a suspended subscription.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_activate
+func (c *Client) ActivateSubscription(ctx context.Context, subscriptionID string, reason string) error {
+	return c.subscriptionAction(ctx, "ActivateSubscription", subscriptionID, "/activate", reason)
+}
+
+// CancelSubscription cancels a subscription, it can not be activated again.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_cancel
+func (c *Client) CancelSubscription(ctx context.Context, subscriptionID string, reason string) error {
+	return c.subscriptionAction(ctx, "CancelSubscription", subscriptionID, "/cancel", reason)
+}
+
+func (c *Client) subscriptionAction(ctx context.Context, name, subscriptionID, action, reason string) error {
+	if subscriptionID == "" {
+		return errors.New("subscriptionID is zero")
+	}
+
+	return c.do(ctx, name, request{method: http.MethodPost, path: subscriptionsPath + "/" + subscriptionID + action,
+		body: subscriptionReasonReq{Reason: reason}}, nil)
+}
+
+// ListSubscriptionTransactions lists the transactions of all pages of a subscription between startTime and endTime.
+// https://developer.paypal.com/docs/api/subscriptions/v1/#subscriptions_transactions
+func (c *Client) ListSubscriptionTransactions(ctx context.Context, subscriptionID string, startTime, endTime time.Time) (transactions []SubscriptionTransaction, err error) {
+	if subscriptionID == "" {
+		return nil, errors.New("subscriptionID is zero")
+	}
+
+	err = c.walk(ctx, pager{name: "ListSubscriptionTransactions", path: subscriptionsPath + "/" + subscriptionID + "/transactions",
+		query:   listSubscriptionTransactionsReq{StartTime: startTime.UTC().Format(time.RFC3339), EndTime: endTime.UTC().Format(time.RFC3339)},
+		newPage: func() pageResp { return &ListSubscriptionTransactionsResp{} }}, func(resp pageResp) error {
+		transactions = append(transactions, resp.(*ListSubscriptionTransactionsResp).Transactions...)
+		return nil
+	})
+	return transactions, err
+}
@@ -0,0 +1,181 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_Product(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + productsPath: {status: http.StatusCreated, body: map[string]interface{}{"id": "PROD-XXCD1234QWER65782", "name": "Video Streaming Service", "type": "SERVICE"}},
+		"GET " + productsPath: {body: map[string]interface{}{
+			"products":    []map[string]string{{"id": "PROD-XXCD1234QWER65782", "name": "Video Streaming Service"}},
+			"total_items": 1, "total_pages": 1,
+		}},
+		"PATCH " + productsPath + "/PROD-XXCD1234QWER65782": {status: http.StatusNoContent},
+	})
+	ctx := context.Background()
+
+	product, err := c.CreateProduct(ctx, CreateProductReq{RequestID: "product-1", Name: "Video Streaming Service", Type: ProductTypeService})
+	if err != nil || product.ID != "PROD-XXCD1234QWER65782" || product.Type != ProductTypeService {
+		t.Fatalf("CreateProduct() got = %+v, err = %v", product, err)
+	}
+	if got := mock.requests[0].Header.Get(headerRequestID); got != "product-1" {
+		t.Errorf("CreateProduct() PayPal-Request-Id = %v", got)
+	}
+
+	products, err := c.ListProducts(ctx, ListReq{Page: 2, PageSize: 10, TotalRequired: true})
+	if err != nil || len(products.Products) != 1 || products.TotalItems != 1 {
+		t.Fatalf("ListProducts() got = %+v, err = %v", products, err)
+	}
+	if got := mock.requests[1].URL.RawQuery; got != "page=2&page_size=10&total_required=true" {
+		t.Errorf("ListProducts() query = %v", got)
+	}
+
+	if err = c.UpdateProduct(ctx, "PROD-XXCD1234QWER65782", []PatchOperation{{Op: "replace", Path: "/description", Value: "Premium"}}); err != nil {
+		t.Errorf("UpdateProduct() err = %v", err)
+	}
+	if _, err = c.GetProduct(ctx, "UNKNOWN"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("GetProduct() err = %v, want %v", err, ErrResourceNotFound)
+	}
+}
+
+func TestClient_Plan(t *testing.T) {
+	plan := map[string]interface{}{
+		"id": "P-5ML4271244454362WXNWU5NQ", "product_id": "PROD-XXCD1234QWER65782", "name": "Basic Plan", "status": "ACTIVE",
+		"billing_cycles": []map[string]interface{}{{
+			"frequency": map[string]interface{}{"interval_unit": "MONTH", "interval_count": 1}, "tenure_type": "REGULAR",
+			"sequence": 1, "total_cycles": 0, "pricing_scheme": map[string]interface{}{"fixed_price": map[string]string{"currency_code": "USD", "value": "10.00"}},
+		}},
+	}
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + plansPath: {status: http.StatusCreated, body: plan},
+		"GET " + plansPath + "/P-5ML4271244454362WXNWU5NQ":                         {body: plan},
+		"POST " + plansPath + "/P-5ML4271244454362WXNWU5NQ/deactivate":             {status: http.StatusNoContent},
+		"POST " + plansPath + "/P-5ML4271244454362WXNWU5NQ/update-pricing-schemes": {status: http.StatusNoContent},
+		"GET " + plansPath: {body: map[string]interface{}{"plans": []interface{}{plan}}},
+	})
+	ctx := context.Background()
+
+	created, err := c.CreatePlan(ctx, CreatePlanReq{
+		ProductID: "PROD-XXCD1234QWER65782",
+		Name:      "Basic Plan",
+		BillingCycles: []BillingCycle{{
+			Frequency:     Frequency{IntervalUnit: IntervalUnitMonth, IntervalCount: 1},
+			TenureType:    TenureTypeRegular,
+			Sequence:      1,
+			PricingScheme: &PricingScheme{FixedPrice: &Money{CurrencyCode: "USD", Value: "10.00"}},
+		}},
+		PaymentPreferences: &PaymentPreferences{AutoBillOutstanding: true, PaymentFailureThreshold: 3},
+	})
+	if err != nil || created.Status != PlanStatusActive || len(created.BillingCycles) != 1 ||
+		created.BillingCycles[0].PricingScheme.FixedPrice.Value != "10.00" {
+		t.Fatalf("CreatePlan() got = %+v, err = %v", created, err)
+	}
+
+	got, err := c.GetPlan(ctx, "P-5ML4271244454362WXNWU5NQ")
+	if err != nil || got.BillingCycles[0].Frequency.IntervalUnit != IntervalUnitMonth {
+		t.Errorf("GetPlan() got = %+v, err = %v", got, err)
+	}
+
+	plans, err := c.ListPlans(ctx, ListPlansReq{ProductID: "PROD-XXCD1234QWER65782", ListReq: ListReq{PageSize: 20}})
+	if err != nil || len(plans.Plans) != 1 {
+		t.Errorf("ListPlans() got = %+v, err = %v", plans, err)
+	}
+	if got := mock.requests[2].URL.Query().Get("product_id"); got != "PROD-XXCD1234QWER65782" {
+		t.Errorf("ListPlans() product_id = %v", got)
+	}
+
+	if err = c.DeactivatePlan(ctx, "P-5ML4271244454362WXNWU5NQ"); err != nil {
+		t.Errorf("DeactivatePlan() err = %v", err)
+	}
+	err = c.UpdatePlanPricing(ctx, "P-5ML4271244454362WXNWU5NQ", []PricingSchemeUpdate{
+		{BillingCycleSequence: 1, PricingScheme: PricingScheme{FixedPrice: &Money{CurrencyCode: "USD", Value: "12.00"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"pricing_schemes":[{"billing_cycle_sequence":1,"pricing_scheme":{"fixed_price":{"currency_code":"USD","value":"12.00"}}}]}`
+	if got := string(mock.bodies[4]); got != want {
+		t.Errorf("UpdatePlanPricing() body = %s, want %s", got, want)
+	}
+}
+
+func TestClient_Subscription(t *testing.T) {
+	subscriptionID := "I-BW452GLLEP1G"
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + subscriptionsPath: {status: http.StatusCreated, body: map[string]interface{}{
+			"id": subscriptionID, "plan_id": "P-5ML4271244454362WXNWU5NQ", "status": "APPROVAL_PENDING",
+			"links": []map[string]string{{"href": "https://www.paypal.com/webapps/billing/subscriptions?ba_token=BA-2M539689T3856352J", "rel": "approve", "method": "GET"}},
+		}},
+		"GET " + subscriptionsPath + "/" + subscriptionID: {body: map[string]interface{}{
+			"id": subscriptionID, "status": "ACTIVE",
+			"billing_info": map[string]interface{}{
+				"last_payment":          map[string]interface{}{"amount": map[string]string{"currency_code": "USD", "value": "10.00"}, "time": "2021-12-01T08:00:00Z"},
+				"next_billing_time":     "2022-01-01T08:00:00Z",
+				"failed_payments_count": 0,
+			},
+		}},
+		"POST " + subscriptionsPath + "/" + subscriptionID + "/revise": {body: map[string]interface{}{
+			"plan_id": "P-UPGRADE", "links": []map[string]string{{"href": "https://www.paypal.com/webapps/billing/subscriptions/update?ba_token=BA-1", "rel": "approve", "method": "GET"}},
+		}},
+		"POST " + subscriptionsPath + "/" + subscriptionID + "/suspend": {status: http.StatusNoContent},
+		"POST " + subscriptionsPath + "/" + subscriptionID + "/activate": {status: http.StatusUnprocessableEntity, body: map[string]interface{}{
+			"name": "UNPROCESSABLE_ENTITY", "details": []map[string]string{{"issue": "SUBSCRIPTION_STATUS_INVALID"}},
+		}},
+		"POST " + subscriptionsPath + "/" + subscriptionID + "/cancel": {status: http.StatusNoContent},
+		"GET " + subscriptionsPath + "/" + subscriptionID + "/transactions": {body: map[string]interface{}{
+			"transactions": []map[string]interface{}{{
+				"id": "9DL25326D9421311E", "status": "COMPLETED", "time": "2021-12-01T08:00:00Z",
+				"amount_with_breakdown": map[string]interface{}{"gross_amount": map[string]string{"currency_code": "USD", "value": "10.00"}},
+			}},
+		}},
+	})
+	ctx := context.Background()
+
+	subscription, err := c.CreateSubscription(ctx, CreateSubscriptionReq{RequestID: "sub-1", PlanID: "P-5ML4271244454362WXNWU5NQ", CustomID: "user-1"})
+	if err != nil || subscription.Status != SubscriptionStatusApprovalPending ||
+		subscription.ApproveURL() != "https://www.paypal.com/webapps/billing/subscriptions?ba_token=BA-2M539689T3856352J" {
+		t.Fatalf("CreateSubscription() got = %+v, err = %v", subscription, err)
+	}
+
+	subscription, err = c.GetSubscription(ctx, subscriptionID)
+	if err != nil || subscription.Status != SubscriptionStatusActive || subscription.BillingInfo == nil ||
+		subscription.BillingInfo.LastPayment.Amount.Value != "10.00" || subscription.BillingInfo.NextBillingTime.Year() != 2022 {
+		t.Fatalf("GetSubscription() got = %+v, err = %v", subscription, err)
+	}
+
+	revised, err := c.ReviseSubscription(ctx, subscriptionID, ReviseSubscriptionReq{PlanID: "P-UPGRADE"})
+	if err != nil || revised.PlanID != "P-UPGRADE" || revised.ApproveURL() == "" {
+		t.Errorf("ReviseSubscription() got = %+v, err = %v", revised, err)
+	}
+
+	if err = c.SuspendSubscription(ctx, subscriptionID, "payment overdue"); err != nil {
+		t.Errorf("SuspendSubscription() err = %v", err)
+	}
+	var body map[string]string
+	_ = json.Unmarshal(mock.bodies[3], &body)
+	if body["reason"] != "payment overdue" {
+		t.Errorf("SuspendSubscription() body = %s", mock.bodies[3])
+	}
+	if err = c.ActivateSubscription(ctx, subscriptionID, "paid"); !errors.Is(err, ErrSubscriptionStatusInvalid) {
+		t.Errorf("ActivateSubscription() err = %v, want %v", err, ErrSubscriptionStatusInvalid)
+	}
+	if err = c.CancelSubscription(ctx, subscriptionID, "user cancelled"); err != nil {
+		t.Errorf("CancelSubscription() err = %v", err)
+	}
+
+	start := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+	transactions, err := c.ListSubscriptionTransactions(ctx, subscriptionID, start, start.AddDate(0, 2, 0))
+	if err != nil || len(transactions) != 1 || transactions[0].AmountWithBreakdown.GrossAmount.Value != "10.00" {
+		t.Fatalf("ListSubscriptionTransactions() got = %+v, err = %v", transactions, err)
+	}
+	query := mock.requests[6].URL.Query()
+	if query.Get("start_time") != "2021-11-01T00:00:00Z" || query.Get("end_time") != "2022-01-01T00:00:00Z" {
+		t.Errorf("ListSubscriptionTransactions() query = %v", query)
+	}
+}
@@ -0,0 +1,201 @@
+package paypal
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linhoi/kit/log"
+)
+
+// refreshTokenBefore is how long before expiry the token is refreshed in background,
+// requests keep using the current token meanwhile.
+const refreshTokenBefore = 5 * time.Minute
+
+// TokenCache shares access tokens between instances of the same app, e.g. backed by redis,
+// so they do not refresh the token one by one.
+type TokenCache interface {
+	// Get returns an empty token if there is no token of key.
+	Get(ctx context.Context, key string) (token string, expireAt time.Time, err error)
+	Set(ctx context.Context, key string, token string, expireAt time.Time) error
+}
+
+// tokenProvider caches the access token, concurrent refreshes are merged into one request.
+type tokenProvider struct {
+	fetch func(ctx context.Context) (token string, expireAt time.Time, err error)
+	key   string
+
+	mu       sync.Mutex
+	token    string
+	expireAt time.Time
+	cache    TokenCache
+	call     *tokenCall
+}
+
+// tokenCall is an in-flight refresh, rejected is the token it must not return.
+type tokenCall struct {
+	done     chan struct{}
+	rejected string
+	token    string
+	err      error
+}
+
+func newTokenProvider(key string, fetch func(ctx context.Context) (string, time.Time, error)) *tokenProvider {
+	return &tokenProvider{key: key, fetch: fetch}
+}
+
+func (p *tokenProvider) setCache(cache TokenCache) {
+	p.mu.Lock()
+	p.cache = cache
+	p.mu.Unlock()
+}
+
+// get returns a token valid for at least getTokenBeforeExpire, it refreshes in background when the token is about to expire.
+func (p *tokenProvider) get(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	now := time.Now()
+	if p.token != "" && now.Add(getTokenBeforeExpire).Before(p.expireAt) {
+		token := p.token
+		if p.call == nil && now.Add(refreshTokenBefore).After(p.expireAt) {
+			call := p.startLocked("")
+			go p.refresh(context.Background(), call)
+		}
+		p.mu.Unlock()
+		return token, nil
+	}
+
+	return p.waitLocked(ctx, "")
+}
+
+// forceRefresh refreshes the token rejected by paypal, unless it has been refreshed already.
+func (p *tokenProvider) forceRefresh(ctx context.Context, rejected string) (string, error) {
+	p.mu.Lock()
+	if p.token != "" && p.token != rejected && time.Now().Add(getTokenBeforeExpire).Before(p.expireAt) {
+		token := p.token
+		p.mu.Unlock()
+		return token, nil
+	}
+
+	// the in-flight refresh may take the rejected token from the cache, wait for it and check its token again
+	if call := p.call; call != nil && call.rejected != rejected {
+		p.mu.Unlock()
+		select {
+		case <-call.done:
+			return p.forceRefresh(ctx, rejected)
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	return p.waitLocked(ctx, rejected)
+}
+
+// waitLocked joins the in-flight refresh or starts one, p.mu must be held and is released.
+// The refresh runs in background, so a caller giving up on its ctx does not fail the others waiting for it.
+func (p *tokenProvider) waitLocked(ctx context.Context, rejected string) (string, error) {
+	call := p.call
+	if call == nil {
+		call = p.startLocked(rejected)
+		go p.refresh(context.Background(), call)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (p *tokenProvider) startLocked(rejected string) *tokenCall {
+	call := &tokenCall{done: make(chan struct{}), rejected: rejected}
+	p.call = call
+	return call
+}
+
+// refresh takes the token from the cache if another instance refreshed it, otherwise fetches a new one.
+func (p *tokenProvider) refresh(ctx context.Context, call *tokenCall) {
+	p.mu.Lock()
+	cache := p.cache
+	p.mu.Unlock()
+
+	token, expireAt, err := p.fromCache(ctx, cache, call.rejected)
+	if token == "" {
+		fetchCtx, cancel := context.WithTimeout(ctx, clientTimeout)
+		token, expireAt, err = p.fetch(fetchCtx)
+		cancel()
+		if err == nil && cache != nil {
+			if cacheErr := cache.Set(ctx, p.key, token, expireAt); cacheErr != nil {
+				log.S(ctx).Errorw("paypal token cache set", "err", cacheErr)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	if err == nil {
+		p.token = token
+		p.expireAt = expireAt
+		log.S(ctx).Infow("get token success", "expireAt", expireAt.Format(time.RFC3339))
+	}
+	p.call = nil
+	p.mu.Unlock()
+
+	call.token, call.err = token, err
+	close(call.done)
+}
+
+func (p *tokenProvider) fromCache(ctx context.Context, cache TokenCache, rejected string) (string, time.Time, error) {
+	if cache == nil {
+		return "", time.Time{}, nil
+	}
+
+	token, expireAt, err := cache.Get(ctx, p.key)
+	if err != nil {
+		log.S(ctx).Errorw("paypal token cache get", "err", err)
+		return "", time.Time{}, nil
+	}
+	if token == "" || token == rejected || time.Now().Add(refreshTokenBefore).After(expireAt) {
+		return "", time.Time{}, nil
+	}
+
+	return token, expireAt, nil
+}
+
+// tokenRetryTransport retries a request once with a new token if paypal rejects the bearer token with 401,
+// e.g. the token is revoked or refreshed by another instance sharing the cache.
+type tokenRetryTransport struct {
+	base   http.RoundTripper
+	tokens *tokenProvider
+}
+
+func (t *tokenRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	rejected := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if rejected == req.Header.Get("Authorization") || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	token, err := t.tokens.forceRefresh(req.Context(), rejected)
+	if err != nil {
+		log.S(req.Context()).Errorw("paypal refresh rejected token", "err", err)
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+	resp.Body.Close()
+
+	return t.base.RoundTrip(retry)
+}
@@ -0,0 +1,268 @@
+package paypal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer issues token-1, token-2... and only accepts the latest token.
+type tokenServer struct {
+	issued    int32
+	expiresIn int
+	requests  int32
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == getAccessTokenPath {
+		n := atomic.AddInt32(&s.issued, 1)
+		time.Sleep(10 * time.Millisecond)
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":%d}`, n, s.expiresIn)
+		return
+	}
+
+	atomic.AddInt32(&s.requests, 1)
+	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", atomic.LoadInt32(&s.issued)) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_token"}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"id":"5O190127TN364715T","status":"CREATED"}`))
+}
+
+func newTokenTestClient(t *testing.T, s *tokenServer) *Client {
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{Host: server.URL, ClientID: "client", Secret: "secret"})
+}
+
+func TestClient_GetAccessTokenConcurrent(t *testing.T) {
+	s := &tokenServer{expiresIn: 32400}
+	c := newTokenTestClient(t, s)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := c.GetAccessToken(context.Background())
+			if err != nil || token != "token-1" {
+				t.Errorf("GetAccessToken() got = %v, err = %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&s.issued); n != 1 {
+		t.Errorf("GetAccessToken() fetched %d tokens, want 1", n)
+	}
+}
+
+func TestTokenProvider_CallerCanceled(t *testing.T) {
+	release := make(chan struct{})
+	var fetched int32
+	p := newTokenProvider("client", func(ctx context.Context) (string, time.Time, error) {
+		atomic.AddInt32(&fetched, 1)
+		select {
+		case <-release:
+			return "token-1", time.Now().Add(time.Hour), nil
+		case <-ctx.Done():
+			return "", time.Time{}, ctx.Err()
+		}
+	})
+
+	// the first caller starts the refresh and gives up, the one waiting for the same refresh still gets the token
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := p.get(ctx)
+		first <- err
+	}()
+	for {
+		p.mu.Lock()
+		started := p.call != nil
+		p.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	second := make(chan string, 1)
+	go func() {
+		token, _ := p.get(context.Background())
+		second <- token
+	}()
+
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Errorf("get() err = %v, want %v", err, context.Canceled)
+	}
+	close(release)
+	if token := <-second; token != "token-1" {
+		t.Errorf("get() got = %v, want token-1", token)
+	}
+	if n := atomic.LoadInt32(&fetched); n != 1 {
+		t.Errorf("get() fetched %d tokens, want 1", n)
+	}
+}
+
+// blockingTokenCache blocks the first Get until release is closed.
+type blockingTokenCache struct {
+	memoryTokenCache
+	release chan struct{}
+	once    sync.Once
+}
+
+func (b *blockingTokenCache) Get(ctx context.Context, key string) (string, time.Time, error) {
+	b.once.Do(func() { <-b.release })
+	return b.memoryTokenCache.Get(ctx, key)
+}
+
+func TestTokenProvider_ForceRefreshDuringRefresh(t *testing.T) {
+	p := newTokenProvider("client", func(ctx context.Context) (string, time.Time, error) {
+		return "token-2", time.Now().Add(time.Hour), nil
+	})
+	cache := &blockingTokenCache{memoryTokenCache: memoryTokenCache{token: "token-1", expireAt: time.Now().Add(time.Hour)}, release: make(chan struct{})}
+	p.setCache(cache)
+
+	// a background refresh reads token-1 from the cache while paypal rejects token-1
+	p.mu.Lock()
+	call := p.startLocked("")
+	p.mu.Unlock()
+	go p.refresh(context.Background(), call)
+
+	got := make(chan string, 1)
+	go func() {
+		token, _ := p.forceRefresh(context.Background(), "token-1")
+		got <- token
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(cache.release)
+
+	if token := <-got; token != "token-2" {
+		t.Errorf("forceRefresh() got = %v, want token-2", token)
+	}
+}
+
+func TestClient_GetAccessTokenExpiry(t *testing.T) {
+	tests := []struct {
+		name       string
+		expiresIn  int
+		wantIssued int32
+	}{
+		{name: "valid token is reused", expiresIn: 32400, wantIssued: 1},
+		{name: "token about to expire is refreshed", expiresIn: 10, wantIssued: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &tokenServer{expiresIn: tt.expiresIn}
+			c := newTokenTestClient(t, s)
+
+			for i := 0; i < 2; i++ {
+				if _, err := c.GetAccessToken(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if n := atomic.LoadInt32(&s.issued); n != tt.wantIssued {
+				t.Errorf("GetAccessToken() fetched %d tokens, want %d", n, tt.wantIssued)
+			}
+		})
+	}
+}
+
+func TestClient_GetAccessTokenProactiveRefresh(t *testing.T) {
+	s := &tokenServer{expiresIn: 120}
+	c := newTokenTestClient(t, s)
+
+	for i := 0; i < 2; i++ {
+		token, err := c.GetAccessToken(context.Background())
+		if err != nil || token != "token-1" {
+			t.Fatalf("GetAccessToken() got = %v, err = %v", token, err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&s.issued) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&s.issued); n != 2 {
+		t.Errorf("token refreshed %d times in background, want 1", n-1)
+	}
+}
+
+func TestClient_RetryOnUnauthorized(t *testing.T) {
+	s := &tokenServer{expiresIn: 32400}
+	c := newTokenTestClient(t, s)
+	ctx := context.Background()
+
+	if _, err := c.GetAccessToken(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// the token is revoked, e.g. another instance refreshed it
+	atomic.AddInt32(&s.issued, 1)
+
+	order, err := c.CreateOrder(ctx, CreateOrderReq{Intent: OrderIntentCapture})
+	if err != nil || order.ID != "5O190127TN364715T" {
+		t.Fatalf("CreateOrder() got = %+v, err = %v", order, err)
+	}
+	if n := atomic.LoadInt32(&s.requests); n != 2 {
+		t.Errorf("CreateOrder() sent %d requests, want 2", n)
+	}
+	if token, _ := c.GetAccessToken(ctx); token != "token-3" {
+		t.Errorf("GetAccessToken() got = %v, want token-3", token)
+	}
+}
+
+type memoryTokenCache struct {
+	mu       sync.Mutex
+	token    string
+	expireAt time.Time
+}
+
+func (m *memoryTokenCache) Get(ctx context.Context, key string) (string, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.token, m.expireAt, nil
+}
+
+func (m *memoryTokenCache) Set(ctx context.Context, key string, token string, expireAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.token, m.expireAt = token, expireAt
+	return nil
+}
+
+func TestClient_SetTokenCache(t *testing.T) {
+	s := &tokenServer{expiresIn: 32400}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	cache := &memoryTokenCache{}
+	config := Config{Host: server.URL, ClientID: "client", Secret: "secret"}
+	a, b := NewClient(config), NewClient(config)
+	a.SetTokenCache(cache)
+	b.SetTokenCache(cache)
+
+	tokenA, err := a.GetAccessToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenB, err := b.GetAccessToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokenA != "token-1" || tokenB != tokenA {
+		t.Errorf("GetAccessToken() got = %v and %v, want shared token-1", tokenA, tokenB)
+	}
+	if n := atomic.LoadInt32(&s.issued); n != 1 {
+		t.Errorf("GetAccessToken() fetched %d tokens, want 1", n)
+	}
+}
@@ -0,0 +1,152 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxTransactionSearchWindow is the longest time range of a transaction search request.
+const maxTransactionSearchWindow = 31 * 24 * time.Hour
+
+const (
+	PaymentInstrumentTypeCreditCard = "CREDITCARD"
+	PaymentInstrumentTypeDebitCard  = "DEBITCARD"
+)
+
+// TransactionDetail is a transaction with the payer, shipping and cart of it when Fields is all.
+// https://developer.paypal.com/docs/api/transaction-search/v1/#definition-transaction_detail
+type TransactionDetail struct {
+	TransactionInfo TransactionInfo `json:"transaction_info"`
+	PayerInfo       PayerInfo       `json:"payer_info"`
+	ShippingInfo    ShippingInfo    `json:"shipping_info"`
+	CartInfo        CartInfo        `json:"cart_info"`
+	StoreInfo       StoreInfo       `json:"store_info"`
+	AuctionInfo     AuctionInfo     `json:"auction_info"`
+	IncentiveInfo   IncentiveInfo   `json:"incentive_info"`
+}
+
+type TransactionAddress struct {
+	Line1       string `json:"line1"`
+	Line2       string `json:"line2"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	CountryCode string `json:"country_code"`
+	PostalCode  string `json:"postal_code"`
+}
+
+type PayerName struct {
+	GivenName         string `json:"given_name"`
+	Surname           string `json:"surname"`
+	AlternateFullName string `json:"alternate_full_name"`
+}
+
+type PayerInfo struct {
+	AccountID    string `json:"account_id"`
+	EmailAddress string `json:"email_address"`
+	PhoneNumber  struct {
+		CountryCode    string `json:"country_code"`
+		NationalNumber string `json:"national_number"`
+	} `json:"phone_number"`
+	AddressStatus string             `json:"address_status"`
+	PayerStatus   string             `json:"payer_status"`
+	PayerName     PayerName          `json:"payer_name"`
+	CountryCode   string             `json:"country_code"`
+	Address       TransactionAddress `json:"address"`
+}
+
+type ShippingInfo struct {
+	Name                     string             `json:"name"`
+	Method                   string             `json:"method"`
+	Address                  TransactionAddress `json:"address"`
+	SecondaryShippingAddress TransactionAddress `json:"secondary_shipping_address"`
+}
+
+type CartItem struct {
+	ItemCode        string `json:"item_code"`
+	ItemName        string `json:"item_name"`
+	ItemDescription string `json:"item_description"`
+	ItemQuantity    string `json:"item_quantity"`
+	ItemUnitPrice   Money  `json:"item_unit_price"`
+	ItemAmount      Money  `json:"item_amount"`
+	DiscountAmount  Money  `json:"discount_amount"`
+	TaxAmounts      []struct {
+		TaxAmount Money `json:"tax_amount"`
+	} `json:"tax_amounts"`
+	TotalItemAmount Money  `json:"total_item_amount"`
+	InvoiceNumber   string `json:"invoice_number"`
+}
+
+type CartInfo struct {
+	ItemDetails     []CartItem `json:"item_details"`
+	TaxInclusive    bool       `json:"tax_inclusive"`
+	PaypalInvoiceID string     `json:"paypal_invoice_id"`
+}
+
+type StoreInfo struct {
+	StoreID    string `json:"store_id"`
+	TerminalID string `json:"terminal_id"`
+}
+
+type AuctionInfo struct {
+	AuctionSite        string `json:"auction_site"`
+	AuctionItemSite    string `json:"auction_item_site"`
+	AuctionBuyerID     string `json:"auction_buyer_id"`
+	AuctionClosingDate string `json:"auction_closing_date"`
+}
+
+type IncentiveInfo struct {
+	IncentiveDetails []struct {
+		IncentiveType        string `json:"incentive_type"`
+		IncentiveCode        string `json:"incentive_code"`
+		IncentiveAmount      Money  `json:"incentive_amount"`
+		IncentiveProgramCode string `json:"incentive_program_code"`
+	} `json:"incentive_details"`
+}
+
+// TransactionAmountRange returns the TransactionAmount filter of the gross amount between min and max in the lower denomination,
+// e.g. 500 and 1005 for $5.00 to $10.05.
+func TransactionAmountRange(min, max int64) string {
+	return fmt.Sprintf("%d TO %d", min, max)
+}
+
+// SearchTransactions calls fn with each page of the transactions matching the filters of req between startTime and endTime.
+// The time range is split into windows of at most 31 days searched from the oldest, StartDate, EndDate and Page of req are ignored.
+// Fields is all and PageSize is 500 if empty, fn returning ErrStopPaging stops the search.
+// https://developer.paypal.com/docs/api/transaction-search/v1/#transactions_get
+func (c *Client) SearchTransactions(ctx context.Context, req TransactionSearchReq, startTime, endTime time.Time,
+	fn func(details []TransactionDetail) error) error {
+	if !startTime.Before(endTime) {
+		return errors.New("startTime is not before endTime")
+	}
+	if req.Fields == "" {
+		req.Fields = "all"
+	}
+	if req.PageSize == 0 {
+		req.PageSize = maxPageSizeForTranscationSearch
+	}
+
+	var stopped bool
+	for windowStart := startTime; windowStart.Before(endTime) && !stopped; windowStart = windowStart.Add(maxTransactionSearchWindow) {
+		windowEnd := windowStart.Add(maxTransactionSearchWindow)
+		if windowEnd.Before(endTime) {
+			// end_date is inclusive, the next window starts at windowEnd
+			windowEnd = windowEnd.Add(-time.Millisecond)
+		} else {
+			windowEnd = endTime
+		}
+
+		req.StartDate, req.EndDate, req.Page = windowStart.UTC().Format(timeFmt), windowEnd.UTC().Format(timeFmt), 1
+		err := c.WalkTransactions(ctx, req, func(page TransactionSearchResp) error {
+			err := fn(page.TransactionDetails)
+			stopped = errors.Is(err, ErrStopPaging)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// transactionSearchServer returns one transaction with payer, shipping and cart per request, and records the queries.
+func transactionSearchServer(t *testing.T) (*Client, func() []url.Values) {
+	var mu sync.Mutex
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == getAccessTokenPath {
+			_ = json.NewEncoder(w).Encode(AccessTokeResp{AccessToken: "A21AA-test", ExpiresIn: 32400})
+			return
+		}
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+
+		_, _ = w.Write([]byte(`{
+  "transaction_details": [{
+    "transaction_info": {"transaction_id": "` + r.URL.Query().Get("start_date") + `", "invoice_id": "INV-1"},
+    "payer_info": {"account_id": "FAKE_ACCOUNT", "email_address": "buyer@example.com", "payer_name": {"given_name": "Jane", "surname": "Doe"}},
+    "shipping_info": {"name": "Jane Doe", "address": {"line1": "1 Main St", "city": "San Jose", "country_code": "US", "postal_code": "95131"}},
+    "cart_info": {"item_details": [{"item_name": "Pass", "item_quantity": "2", "item_unit_price": {"currency_code": "USD", "value": "5.00"},
+      "tax_amounts": [{"tax_amount": {"currency_code": "USD", "value": "0.50"}}]}]}
+  }],
+  "page": 1, "total_pages": 1
+}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{Host: server.URL, ClientID: "client", Secret: "secret"}), func() []url.Values {
+		mu.Lock()
+		defer mu.Unlock()
+		return queries
+	}
+}
+
+func TestClient_SearchTransactions(t *testing.T) {
+	c, queries := transactionSearchServer(t)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 70)
+
+	var details []TransactionDetail
+	err := c.SearchTransactions(context.Background(), TransactionSearchReq{
+		InvoiceID:                   "INV-1",
+		TransactionType:             "T0006",
+		TransactionAmount:           TransactionAmountRange(500, 1005),
+		TransactionCurrency:         "USD",
+		PaymentInstrumentType:       PaymentInstrumentTypeCreditCard,
+		BalanceAffectingRecordsOnly: "Y",
+	}, start, end, func(page []TransactionDetail) error {
+		details = append(details, page...)
+		return nil
+	})
+	if err != nil || len(details) != 3 {
+		t.Fatalf("SearchTransactions() got = %d details, err = %v", len(details), err)
+	}
+
+	wantWindows := [][2]string{
+		{"2021-01-01T00:00:00.000Z", "2021-01-31T23:59:59.999Z"},
+		{"2021-02-01T00:00:00.000Z", "2021-03-03T23:59:59.999Z"},
+		{"2021-03-04T00:00:00.000Z", "2021-03-12T00:00:00.000Z"},
+	}
+	for i, query := range queries() {
+		if got := [2]string{query.Get("start_date"), query.Get("end_date")}; got != wantWindows[i] {
+			t.Errorf("SearchTransactions() window %d = %v, want %v", i, got, wantWindows[i])
+		}
+	}
+	query := queries()[0]
+	for key, want := range map[string]string{
+		"invoice_id": "INV-1", "transaction_type": "T0006", "transaction_amount": "500 TO 1005", "transaction_currency": "USD",
+		"payment_instrument_type": "CREDITCARD", "balance_affecting_records_only": "Y", "fields": "all", "page_size": "500", "page": "1",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("SearchTransactions() %s = %v, want %v", key, got, want)
+		}
+	}
+
+	detail := details[0]
+	if detail.PayerInfo.PayerName.GivenName != "Jane" || detail.ShippingInfo.Address.City != "San Jose" ||
+		detail.CartInfo.ItemDetails[0].ItemUnitPrice.Value != "5.00" || detail.CartInfo.ItemDetails[0].TaxAmounts[0].TaxAmount.Value != "0.50" ||
+		detail.TransactionInfo.GetInvoiceID() != "INV-1" {
+		t.Errorf("SearchTransactions() detail = %+v", detail)
+	}
+
+	if err = c.SearchTransactions(context.Background(), TransactionSearchReq{}, end, start, nil); err == nil {
+		t.Errorf("SearchTransactions() err = nil, want startTime is not before endTime")
+	}
+}
+
+func TestClient_GetTransactionWindows(t *testing.T) {
+	c, queries := transactionSearchServer(t)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// the first window has the transaction, the others are not searched
+	transaction, err := c.GetTransaction(context.Background(), "5TY05013RG002845M", start, start.AddDate(1, 0, 0))
+	if err != nil || transaction.TransactionId != "2021-01-01T00:00:00.000Z" {
+		t.Fatalf("GetTransaction() got = %+v, err = %v", transaction, err)
+	}
+	if got := queries(); len(got) != 1 || got[0].Get("transaction_id") != "5TY05013RG002845M" {
+		t.Errorf("GetTransaction() queries = %v", got)
+	}
+}
+
+func TestClient_GetBalances(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"GET " + balancesPath: {body: map[string]interface{}{
+			"account_id": "MERCHANT1", "as_of_time": "2021-12-01T00:00:00Z", "last_refresh_time": "2021-11-30T22:00:00Z",
+			"balances": []map[string]interface{}{{
+				"currency": "USD", "primary": true,
+				"total_balance":     map[string]string{"currency_code": "USD", "value": "100.00"},
+				"available_balance": map[string]string{"currency_code": "USD", "value": "90.00"},
+				"withheld_balance":  map[string]string{"currency_code": "USD", "value": "10.00"},
+			}},
+		}},
+	})
+
+	resp, err := c.GetBalances(context.Background(), "USD", time.Date(2021, 12, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600)))
+	if err != nil || len(resp.Balances) != 1 || !resp.Balances[0].Primary || resp.Balances[0].AvailableBalance.Value != "90.00" ||
+		resp.LastRefreshTime.Hour() != 22 {
+		t.Fatalf("GetBalances() got = %+v, err = %v", resp, err)
+	}
+	if got := mock.requests[0].URL.RawQuery; got != "as_of_time=2021-12-01T00%3A00%3A00Z&currency_code=USD" {
+		t.Errorf("GetBalances() query = %v", got)
+	}
+}
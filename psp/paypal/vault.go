@@ -0,0 +1,261 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	setupTokensPath   = "/v3/vault/setup-tokens"   // https://developer.paypal.com/docs/api/payment-tokens/v3/#setup-tokens
+	paymentTokensPath = "/v3/vault/payment-tokens" // https://developer.paypal.com/docs/api/payment-tokens/v3/#payment-tokens
+)
+
+// SetupTokenStatus https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-payment_token_status
+type SetupTokenStatus string
+
+const (
+	SetupTokenStatusCreated             SetupTokenStatus = "CREATED"
+	SetupTokenStatusPayerActionRequired SetupTokenStatus = "PAYER_ACTION_REQUIRED"
+	SetupTokenStatusApproved            SetupTokenStatus = "APPROVED"
+	SetupTokenStatusVaulted             SetupTokenStatus = "VAULTED"
+	SetupTokenStatusTokenized           SetupTokenStatus = "TOKENIZED"
+)
+
+type TokenType string
+
+const (
+	TokenTypeSetupToken TokenType = "SETUP_TOKEN"
+)
+
+type UsageType string
+
+const (
+	UsageTypeMerchant UsageType = "MERCHANT"
+	UsageTypePlatform UsageType = "PLATFORM"
+)
+
+type UsagePattern string
+
+const (
+	UsagePatternImmediate           UsagePattern = "IMMEDIATE"
+	UsagePatternDeferred            UsagePattern = "DEFERRED"
+	UsagePatternRecurringPrepaid    UsagePattern = "RECURRING_PREPAID"
+	UsagePatternRecurringPostpaid   UsagePattern = "RECURRING_POSTPAID"
+	UsagePatternUnscheduledPrepaid  UsagePattern = "UNSCHEDULED_PREPAID"
+	UsagePatternUnscheduledPostpaid UsagePattern = "UNSCHEDULED_POSTPAID"
+)
+
+// VaultCustomer is the customer the tokens are saved for, the id is generated by paypal for the first token
+// and should be stored to save and list the tokens of a returning customer.
+type VaultCustomer struct {
+	ID                 string `json:"id,omitempty"`
+	MerchantCustomerID string `json:"merchant_customer_id,omitempty"`
+}
+
+// VaultExperienceContext https://developer.paypal.com/docs/api/payment-tokens/v3/#definition-experience_context
+type VaultExperienceContext struct {
+	BrandName          string `json:"brand_name,omitempty"`
+	Locale             string `json:"locale,omitempty"`
+	ReturnURL          string `json:"return_url,omitempty"`
+	CancelURL          string `json:"cancel_url,omitempty"`
+	ShippingPreference string `json:"shipping_preference,omitempty"`
+	VaultInstruction   string `json:"vault_instruction,omitempty"`
+}
+
+// CardSource is a card of a payment source, the number and security code are only sent to save a card,
+// paypal returns the brand and last digits instead. Expiry is in the format of YYYY-MM.
+type CardSource struct {
+	Name               string                  `json:"name,omitempty"`
+	Number             string                  `json:"number,omitempty"`
+	Expiry             string                  `json:"expiry,omitempty"`
+	SecurityCode       string                  `json:"security_code,omitempty"`
+	Brand              string                  `json:"brand,omitempty"`
+	LastDigits         string                  `json:"last_digits,omitempty"`
+	BillingAddress     *Address                `json:"billing_address,omitempty"`
+	VerificationMethod string                  `json:"verification_method,omitempty"`
+	ExperienceContext  *VaultExperienceContext `json:"experience_context,omitempty"`
+	// VaultID charges a saved card in an order.
+	VaultID string `json:"vault_id,omitempty"`
+}
+
+// PayPalWalletSource is a paypal wallet of a payment source.
+type PayPalWalletSource struct {
+	Description                 string                  `json:"description,omitempty"`
+	UsagePattern                UsagePattern            `json:"usage_pattern,omitempty"`
+	UsageType                   UsageType               `json:"usage_type,omitempty"`
+	CustomerType                string                  `json:"customer_type,omitempty"`
+	PermitMultiplePaymentTokens bool                    `json:"permit_multiple_payment_tokens,omitempty"`
+	ExperienceContext           *VaultExperienceContext `json:"experience_context,omitempty"`
+	EmailAddress                string                  `json:"email_address,omitempty"`
+	PayerID                     string                  `json:"payer_id,omitempty"`
+	AccountID                   string                  `json:"account_id,omitempty"`
+	Name                        *Name                   `json:"name,omitempty"`
+	// VaultID charges a saved paypal wallet in an order.
+	VaultID string `json:"vault_id,omitempty"`
+}
+
+// TokenSource refers to an approved setup token when it is saved as a payment token.
+type TokenSource struct {
+	ID   string    `json:"id"`
+	Type TokenType `json:"type"`
+}
+
+// PaymentSource is the payment_source of tokens and orders, only one of the fields is set.
+type PaymentSource struct {
+	Card   *CardSource         `json:"card,omitempty"`
+	PayPal *PayPalWalletSource `json:"paypal,omitempty"`
+	Token  *TokenSource        `json:"token,omitempty"`
+}
+
+type CreateSetupTokenReq struct {
+	// RequestID is sent as PayPal-Request-Id, requests with the same id create the token only once.
+	RequestID     string         `json:"-"`
+	Customer      *VaultCustomer `json:"customer,omitempty"`
+	PaymentSource PaymentSource  `json:"payment_source"`
+}
+
+// SetupToken is a payment method waiting for the approval of the payer, it expires in 3 days.
+type SetupToken struct {
+	ID            string           `json:"id"`
+	Customer      *VaultCustomer   `json:"customer,omitempty"`
+	Status        SetupTokenStatus `json:"status"`
+	PaymentSource PaymentSource    `json:"payment_source"`
+	Links         []HATEOASLink    `json:"links"`
+}
+
+// ApproveURL returns the url the payer should be redirected to for a paypal wallet.
+func (t SetupToken) ApproveURL() string {
+	return approveURL(t.Links)
+}
+
+// PaymentToken is a saved payment method of a customer.
+type PaymentToken struct {
+	ID            string         `json:"id"`
+	Customer      *VaultCustomer `json:"customer,omitempty"`
+	PaymentSource PaymentSource  `json:"payment_source"`
+	CreateTime    time.Time      `json:"create_time"`
+	Links         []HATEOASLink  `json:"links"`
+}
+
+// vaultedSource returns the payment source of an order charging the token.
+func (t PaymentToken) vaultedSource() (*PaymentSource, error) {
+	switch {
+	case t.ID == "":
+		return nil, errors.New("paymentTokenID is zero")
+	case t.PaymentSource.Card != nil:
+		return &PaymentSource{Card: &CardSource{VaultID: t.ID}}, nil
+	case t.PaymentSource.PayPal != nil:
+		return &PaymentSource{PayPal: &PayPalWalletSource{VaultID: t.ID}}, nil
+	default:
+		return nil, errors.New("payment token has no card or paypal source")
+	}
+}
+
+type createPaymentTokenReq struct {
+	PaymentSource PaymentSource `json:"payment_source"`
+}
+
+type ListPaymentTokensReq struct {
+	ListReq
+	CustomerID string `url:"customer_id"`
+}
+
+type ListPaymentTokensResp struct {
+	Customer      VaultCustomer  `json:"customer"`
+	PaymentTokens []PaymentToken `json:"payment_tokens"`
+	TotalItems    int            `json:"total_items"`
+	TotalPages    int            `json:"total_pages"`
+	Links         []HATEOASLink  `json:"links"`
+}
+
+func (r ListPaymentTokensResp) pagination() pagination {
+	return pagination{links: r.Links, totalPages: r.TotalPages, items: len(r.PaymentTokens)}
+}
+
+// CreateSetupToken starts saving a card or paypal wallet, a paypal wallet is approved by the payer at ApproveURL.
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#setup-tokens_create
+func (c *Client) CreateSetupToken(ctx context.Context, req CreateSetupTokenReq) (token SetupToken, err error) {
+	err = c.do(ctx, "CreateSetupToken", request{method: http.MethodPost, path: setupTokensPath, body: req, requestID: req.RequestID}, &token)
+	return token, err
+}
+
+// GetSetupToken https://developer.paypal.com/docs/api/payment-tokens/v3/#setup-tokens_get
+func (c *Client) GetSetupToken(ctx context.Context, setupTokenID string) (token SetupToken, err error) {
+	if setupTokenID == "" {
+		return token, errors.New("setupTokenID is zero")
+	}
+
+	err = c.do(ctx, "GetSetupToken", request{method: http.MethodGet, path: setupTokensPath + "/" + setupTokenID}, &token)
+	return token, err
+}
+
+// CreatePaymentToken saves an approved setup token as a payment token of the customer.
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#payment-tokens_create
+func (c *Client) CreatePaymentToken(ctx context.Context, setupTokenID string, requestID string) (token PaymentToken, err error) {
+	if setupTokenID == "" {
+		return token, errors.New("setupTokenID is zero")
+	}
+
+	req := createPaymentTokenReq{PaymentSource: PaymentSource{Token: &TokenSource{ID: setupTokenID, Type: TokenTypeSetupToken}}}
+	err = c.do(ctx, "CreatePaymentToken", request{method: http.MethodPost, path: paymentTokensPath, body: req, requestID: requestID}, &token)
+	return token, err
+}
+
+// GetPaymentToken https://developer.paypal.com/docs/api/payment-tokens/v3/#payment-tokens_get
+func (c *Client) GetPaymentToken(ctx context.Context, paymentTokenID string) (token PaymentToken, err error) {
+	if paymentTokenID == "" {
+		return token, errors.New("paymentTokenID is zero")
+	}
+
+	err = c.do(ctx, "GetPaymentToken", request{method: http.MethodGet, path: paymentTokensPath + "/" + paymentTokenID}, &token)
+	return token, err
+}
+
+// ListPaymentTokens returns one page of the payment tokens of a customer.
+// https://developer.paypal.com/docs/api/payment-tokens/v3/#customer_payment-tokens_get
+func (c *Client) ListPaymentTokens(ctx context.Context, req ListPaymentTokensReq) (resp ListPaymentTokensResp, err error) {
+	if req.CustomerID == "" {
+		return resp, errors.New("customerID is zero")
+	}
+
+	err = c.do(ctx, "ListPaymentTokens", request{method: http.MethodGet, path: paymentTokensPath, query: req}, &resp)
+	return resp, err
+}
+
+// WalkPaymentTokens calls fn with each page of the payment tokens of a customer.
+func (c *Client) WalkPaymentTokens(ctx context.Context, req ListPaymentTokensReq, fn func(page ListPaymentTokensResp) error) error {
+	if req.CustomerID == "" {
+		return errors.New("customerID is zero")
+	}
+
+	return c.walk(ctx, pager{name: "WalkPaymentTokens", path: paymentTokensPath, query: req,
+		newPage: func() pageResp { return &ListPaymentTokensResp{} }}, func(resp pageResp) error {
+		return fn(*resp.(*ListPaymentTokensResp))
+	})
+}
+
+// DeletePaymentToken https://developer.paypal.com/docs/api/payment-tokens/v3/#payment-tokens_delete
+func (c *Client) DeletePaymentToken(ctx context.Context, paymentTokenID string) error {
+	if paymentTokenID == "" {
+		return errors.New("paymentTokenID is zero")
+	}
+
+	return c.do(ctx, "DeletePaymentToken", request{method: http.MethodDelete, path: paymentTokensPath + "/" + paymentTokenID}, nil)
+}
+
+// ChargePaymentToken creates an order paid by the payment token of a returning customer,
+// paypal processes the order without the approval of the payer, so it is COMPLETED if the payment succeeded.
+// The intent is CAPTURE if req.Intent is empty, req.PaymentSource is replaced by the token.
+func (c *Client) ChargePaymentToken(ctx context.Context, token PaymentToken, req CreateOrderReq) (order Order, err error) {
+	if req.PaymentSource, err = token.vaultedSource(); err != nil {
+		return order, err
+	}
+	if req.Intent == "" {
+		req.Intent = OrderIntentCapture
+	}
+
+	err = c.do(ctx, "ChargePaymentToken", request{method: http.MethodPost, path: ordersPath, body: req, requestID: req.RequestID, prefer: true}, &order)
+	return order, err
+}
@@ -0,0 +1,88 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestClient_Vault(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + setupTokensPath: {body: map[string]interface{}{
+			"id": "5C991763VB2781612", "status": "PAYER_ACTION_REQUIRED", "customer": map[string]string{"id": "customer_4029352050"},
+			"links": []HATEOASLink{{Href: "https://www.paypal.com/agreements/approve?approval_session_id=5C991763VB2781612", Rel: "approve", Method: "GET"}},
+		}},
+		"POST " + paymentTokensPath: {body: map[string]interface{}{
+			"id": "8kk8451t", "customer": map[string]string{"id": "customer_4029352050"},
+			"payment_source": map[string]interface{}{"paypal": map[string]string{"email_address": "buyer@example.com", "payer_id": "UBGZ3P2BCU8LC"}},
+		}},
+		"GET " + paymentTokensPath: {body: map[string]interface{}{
+			"customer": map[string]string{"id": "customer_4029352050"},
+			"payment_tokens": []map[string]interface{}{
+				{"id": "8kk8451t", "payment_source": map[string]interface{}{"paypal": map[string]string{"email_address": "buyer@example.com"}}},
+				{"id": "fgh6561t", "payment_source": map[string]interface{}{"card": map[string]string{"brand": "VISA", "last_digits": "1111", "expiry": "2027-02"}}},
+			},
+			"total_items": 2, "total_pages": 1,
+		}},
+		"DELETE " + paymentTokensPath + "/fgh6561t": {status: http.StatusNoContent},
+		"POST " + ordersPath:                        {status: http.StatusCreated, body: map[string]interface{}{"id": "5O190127TN364715T", "status": "COMPLETED"}},
+	})
+	ctx := context.Background()
+
+	setupToken, err := c.CreateSetupToken(ctx, CreateSetupTokenReq{RequestID: "setup-1", PaymentSource: PaymentSource{PayPal: &PayPalWalletSource{
+		UsageType: UsageTypeMerchant, UsagePattern: UsagePatternImmediate,
+		ExperienceContext: &VaultExperienceContext{ReturnURL: "https://example.com/returnUrl", CancelURL: "https://example.com/cancelUrl"},
+	}}})
+	if err != nil || setupToken.Status != SetupTokenStatusPayerActionRequired || setupToken.ApproveURL() == "" {
+		t.Fatalf("CreateSetupToken() got = %+v, err = %v", setupToken, err)
+	}
+	if got := mock.requests[0].Header.Get(headerRequestID); got != "setup-1" {
+		t.Errorf("CreateSetupToken() PayPal-Request-Id = %v", got)
+	}
+
+	paymentToken, err := c.CreatePaymentToken(ctx, setupToken.ID, "payment-token-1")
+	if err != nil || paymentToken.Customer.ID != "customer_4029352050" || paymentToken.PaymentSource.PayPal.PayerID != "UBGZ3P2BCU8LC" {
+		t.Fatalf("CreatePaymentToken() got = %+v, err = %v", paymentToken, err)
+	}
+	var body map[string]map[string]TokenSource
+	if err = json.Unmarshal(mock.bodies[1], &body); err != nil ||
+		body["payment_source"]["token"] != (TokenSource{ID: "5C991763VB2781612", Type: TokenTypeSetupToken}) {
+		t.Errorf("CreatePaymentToken() body = %s", mock.bodies[1])
+	}
+
+	var tokens []PaymentToken
+	err = c.WalkPaymentTokens(ctx, ListPaymentTokensReq{CustomerID: "customer_4029352050"}, func(page ListPaymentTokensResp) error {
+		tokens = append(tokens, page.PaymentTokens...)
+		return nil
+	})
+	if err != nil || len(tokens) != 2 || tokens[1].PaymentSource.Card.LastDigits != "1111" {
+		t.Fatalf("WalkPaymentTokens() got = %+v, err = %v", tokens, err)
+	}
+	if got := mock.requests[2].URL.Query().Get("customer_id"); got != "customer_4029352050" {
+		t.Errorf("WalkPaymentTokens() customer_id = %v", got)
+	}
+	if _, err = c.ListPaymentTokens(ctx, ListPaymentTokensReq{}); err == nil {
+		t.Errorf("ListPaymentTokens() err = nil, want customerID is zero")
+	}
+
+	if err = c.DeletePaymentToken(ctx, "fgh6561t"); err != nil {
+		t.Errorf("DeletePaymentToken() err = %v", err)
+	}
+
+	order, err := c.ChargePaymentToken(ctx, tokens[0], CreateOrderReq{RequestID: "order-1", PurchaseUnits: []PurchaseUnit{
+		{Amount: &AmountWithBreakdown{CurrencyCode: "USD", Value: "10.00"}},
+	}})
+	if err != nil || order.Status != OrderStatusCompleted {
+		t.Fatalf("ChargePaymentToken() got = %+v, err = %v", order, err)
+	}
+	var orderReq CreateOrderReq
+	if err = json.Unmarshal(mock.bodies[4], &orderReq); err != nil || orderReq.Intent != OrderIntentCapture ||
+		orderReq.PaymentSource.PayPal == nil || orderReq.PaymentSource.PayPal.VaultID != "8kk8451t" || orderReq.PaymentSource.Card != nil {
+		t.Errorf("ChargePaymentToken() body = %s", mock.bodies[4])
+	}
+
+	if _, err = c.ChargePaymentToken(ctx, PaymentToken{ID: "8kk8451t"}, CreateOrderReq{}); err == nil {
+		t.Errorf("ChargePaymentToken() err = nil, want no card or paypal source")
+	}
+}
@@ -7,6 +7,13 @@ const (
 	SetExpressCheckout NVPMethod = "SetExpressCheckout"
 	GetExpressCheckoutDetails NVPMethod = "GetExpressCheckoutDetails"
 	DoExpressCheckoutPayment NVPMethod = "DoExpressCheckoutPayment"
+	CreateBillingAgreement NVPMethod = "CreateBillingAgreement"
+	DoReferenceTransaction NVPMethod = "DoReferenceTransaction"
+	// BillAgreementUpdate is the NVP method of the BAUpdate API operation
+	BillAgreementUpdate NVPMethod = "BillAgreementUpdate"
+	RefundTransaction NVPMethod = "RefundTransaction"
+	GetTransactionDetails NVPMethod = "GetTransactionDetails"
+	TransactionSearch NVPMethod = "TransactionSearch"
 
 	Version124 NVPVersion = "124.0"
 )
\ No newline at end of file
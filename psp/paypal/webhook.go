@@ -0,0 +1,225 @@
+package paypal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/linhoi/kit/log"
+)
+
+const (
+	verifyWebhookSignaturePath = "/v1/notifications/verify-webhook-signature" // https://developer.paypal.com/docs/api/webhooks/v1/#verify-webhook-signature_post
+	webhooksPath               = "/v1/notifications/webhooks"                 // https://developer.paypal.com/docs/api/webhooks/v1/#webhooks
+	webhookEventTypesPath      = "/v1/notifications/webhooks-event-types"     // https://developer.paypal.com/docs/api/webhooks/v1/#webhooks-event-types_list
+	simulateEventPath          = "/v1/notifications/simulate-event"           // https://developer.paypal.com/docs/api/webhooks/v1/#simulate-event_post
+
+	// EventTypeAll subscribes a webhook to all events.
+	EventTypeAll = "*"
+
+	VerificationStatusSuccess = "SUCCESS"
+	VerificationStatusFailure = "FAILURE"
+)
+
+// VerifyWebhookSignatureReq is built from the PAYPAL-TRANSMISSION-* headers and the raw body of a webhook.
+type VerifyWebhookSignatureReq struct {
+	AuthAlgo         string `json:"auth_algo"`
+	CertURL          string `json:"cert_url"`
+	TransmissionID   string `json:"transmission_id"`
+	TransmissionSig  string `json:"transmission_sig"`
+	TransmissionTime string `json:"transmission_time"`
+	WebhookID        string `json:"webhook_id"`
+	// WebhookEvent must be the raw body, re-encoding the event breaks the signature.
+	WebhookEvent json.RawMessage `json:"webhook_event"`
+}
+
+type VerifyWebhookSignatureResp struct {
+	VerificationStatus string `json:"verification_status"`
+}
+
+// VerifyWebhookSignature https://developer.paypal.com/docs/api/webhooks/v1/#verify-webhook-signature_post
+func (c *Client) VerifyWebhookSignature(ctx context.Context, req VerifyWebhookSignatureReq) (resp VerifyWebhookSignatureResp, err error) {
+	err = c.do(ctx, "VerifyWebhookSignature", request{method: http.MethodPost, path: verifyWebhookSignaturePath, body: req}, &resp)
+	return resp, err
+}
+
+// WebhookEvent is the envelope of a webhook event, Resource is decoded by the type of the event.
+// https://developer.paypal.com/docs/api/webhooks/v1/#definition-event
+type WebhookEvent struct {
+	ID              string          `json:"id"`
+	CreateTime      time.Time       `json:"create_time"`
+	ResourceType    string          `json:"resource_type"`
+	EventType       string          `json:"event_type"`
+	EventVersion    string          `json:"event_version"`
+	ResourceVersion string          `json:"resource_version"`
+	Summary         string          `json:"summary"`
+	Resource        json.RawMessage `json:"resource"`
+	Links           []HATEOASLink   `json:"links"`
+}
+
+// DecodeResource decodes the resource of the event into v.
+func (e *WebhookEvent) DecodeResource(v interface{}) error {
+	return json.Unmarshal(e.Resource, v)
+}
+
+// EventType https://developer.paypal.com/docs/api/webhooks/v1/#definition-event_type
+type EventType struct {
+	Name             string   `json:"name"`
+	Description      string   `json:"description,omitempty"`
+	Status           string   `json:"status,omitempty"`
+	ResourceVersions []string `json:"resource_versions,omitempty"`
+}
+
+// Webhook https://developer.paypal.com/docs/api/webhooks/v1/#definition-webhook
+type Webhook struct {
+	ID         string        `json:"id,omitempty"`
+	URL        string        `json:"url"`
+	EventTypes []EventType   `json:"event_types"`
+	Links      []HATEOASLink `json:"links,omitempty"`
+}
+
+// EventTypeNames returns the sorted names of the subscribed event types.
+func (w Webhook) EventTypeNames() []string {
+	names := make([]string, 0, len(w.EventTypes))
+	for _, eventType := range w.EventTypes {
+		names = append(names, eventType.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type listWebhooksResp struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type listEventTypesResp struct {
+	EventTypes []EventType `json:"event_types"`
+}
+
+// SimulateEventReq simulates an event to WebhookID, or to URL if WebhookID is empty.
+type SimulateEventReq struct {
+	WebhookID       string `json:"webhook_id,omitempty"`
+	URL             string `json:"url,omitempty"`
+	EventType       string `json:"event_type"`
+	ResourceVersion string `json:"resource_version,omitempty"`
+}
+
+func eventTypesOf(names []string) []EventType {
+	eventTypes := make([]EventType, 0, len(names))
+	for _, name := range names {
+		eventTypes = append(eventTypes, EventType{Name: name})
+	}
+	return eventTypes
+}
+
+// CreateWebhook subscribes url to the event types, a url can only be subscribed once.
+// https://developer.paypal.com/docs/api/webhooks/v1/#webhooks_post
+func (c *Client) CreateWebhook(ctx context.Context, url string, eventTypes []string) (webhook Webhook, err error) {
+	err = c.do(ctx, "CreateWebhook", request{method: http.MethodPost, path: webhooksPath, body: Webhook{URL: url, EventTypes: eventTypesOf(eventTypes)}}, &webhook)
+	return webhook, err
+}
+
+// ListWebhooks https://developer.paypal.com/docs/api/webhooks/v1/#webhooks_list
+func (c *Client) ListWebhooks(ctx context.Context) (webhooks []Webhook, err error) {
+	var resp listWebhooksResp
+	err = c.do(ctx, "ListWebhooks", request{method: http.MethodGet, path: webhooksPath}, &resp)
+	return resp.Webhooks, err
+}
+
+// GetWebhook https://developer.paypal.com/docs/api/webhooks/v1/#webhooks_get
+func (c *Client) GetWebhook(ctx context.Context, webhookID string) (webhook Webhook, err error) {
+	if webhookID == "" {
+		return webhook, errors.New("webhookID is zero")
+	}
+
+	err = c.do(ctx, "GetWebhook", request{method: http.MethodGet, path: webhooksPath + "/" + webhookID}, &webhook)
+	return webhook, err
+}
+
+// UpdateWebhook replaces the url and event types of a webhook, empty values are left unchanged.
+// https://developer.paypal.com/docs/api/webhooks/v1/#webhooks_update
+func (c *Client) UpdateWebhook(ctx context.Context, webhookID string, url string, eventTypes []string) (webhook Webhook, err error) {
+	if webhookID == "" {
+		return webhook, errors.New("webhookID is zero")
+	}
+
+	var operations []PatchOperation
+	if url != "" {
+		operations = append(operations, PatchOperation{Op: "replace", Path: "/url", Value: url})
+	}
+	if len(eventTypes) > 0 {
+		operations = append(operations, PatchOperation{Op: "replace", Path: "/event_types", Value: eventTypesOf(eventTypes)})
+	}
+	if len(operations) == 0 {
+		return c.GetWebhook(ctx, webhookID)
+	}
+
+	err = c.do(ctx, "UpdateWebhook", request{method: http.MethodPatch, path: webhooksPath + "/" + webhookID, body: operations}, &webhook)
+	return webhook, err
+}
+
+// DeleteWebhook https://developer.paypal.com/docs/api/webhooks/v1/#webhooks_delete
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
+	if webhookID == "" {
+		return errors.New("webhookID is zero")
+	}
+
+	return c.do(ctx, "DeleteWebhook", request{method: http.MethodDelete, path: webhooksPath + "/" + webhookID}, nil)
+}
+
+// ListEventTypes lists the event types a webhook can subscribe to.
+// https://developer.paypal.com/docs/api/webhooks/v1/#webhooks-event-types_list
+func (c *Client) ListEventTypes(ctx context.Context) (eventTypes []EventType, err error) {
+	var resp listEventTypesResp
+	err = c.do(ctx, "ListEventTypes", request{method: http.MethodGet, path: webhookEventTypesPath}, &resp)
+	return resp.EventTypes, err
+}
+
+// SimulateEvent sends a sample event to a webhook, the event is signed but not retried.
+// https://developer.paypal.com/docs/api/webhooks/v1/#simulate-event_post
+func (c *Client) SimulateEvent(ctx context.Context, req SimulateEventReq) (event WebhookEvent, err error) {
+	err = c.do(ctx, "SimulateEvent", request{method: http.MethodPost, path: simulateEventPath, body: req}, &event)
+	return event, err
+}
+
+// EnsureWebhook makes url subscribed to exactly the event types,
+// it creates the webhook or updates its event types if they differ.
+func (c *Client) EnsureWebhook(ctx context.Context, url string, eventTypes []string) (webhook Webhook, err error) {
+	webhooks, err := c.ListWebhooks(ctx)
+	if err != nil {
+		return webhook, err
+	}
+
+	for _, w := range webhooks {
+		if w.URL != url {
+			continue
+		}
+
+		want := append([]string(nil), eventTypes...)
+		sort.Strings(want)
+		if equalStrings(w.EventTypeNames(), want) {
+			return w, nil
+		}
+
+		log.S(ctx).Infow("EnsureWebhook update event types", "webhookID", w.ID, "from", w.EventTypeNames(), "to", want)
+		return c.UpdateWebhook(ctx, w.ID, "", eventTypes)
+	}
+
+	log.S(ctx).Infow("EnsureWebhook create", "url", url, "eventTypes", eventTypes)
+	return c.CreateWebhook(ctx, url, eventTypes)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,245 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/linhoi/gopay/psp/paypal"
+	"github.com/linhoi/kit/log"
+)
+
+// maxBodySize limits the body of a webhook, paypal events are far smaller.
+const maxBodySize = 1 << 20
+
+// HandlerFunc deals a verified event, paypal resends the event if it returns an error.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Handler is an http.Handler receiving paypal webhooks,
+// it verifies the signature and dispatches events to the handlers of their types.
+type Handler struct {
+	verifier Verifier
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	fallback HandlerFunc
+}
+
+func NewHandler(verifier Verifier) *Handler {
+	return &Handler{
+		verifier: verifier,
+		handlers: make(map[string]HandlerFunc),
+	}
+}
+
+// Handle registers the handler of an event type.
+func (h *Handler) Handle(eventType string, fn HandlerFunc) {
+	h.mu.Lock()
+	h.handlers[eventType] = fn
+	h.mu.Unlock()
+}
+
+// HandleDefault registers the handler of event types without a handler, those events are ignored by default.
+func (h *Handler) HandleDefault(fn HandlerFunc) {
+	h.mu.Lock()
+	h.fallback = fn
+	h.mu.Unlock()
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		log.S(ctx).Errorw("paypal webhook read body", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	transmission, err := ReadTransmission(r.Header)
+	if err != nil {
+		log.S(ctx).Errorw("paypal webhook read transmission", "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err = h.verifier.Verify(ctx, transmission, body); err != nil {
+		log.S(ctx).Errorw("paypal webhook verify", "transmissionID", transmission.ID, "err", err)
+		if errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrInvalidCert) || errors.Is(err, ErrTransmissionExpired) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// paypal or the cert host is unavailable, let paypal retry
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var event Event
+	if err = json.Unmarshal(body, &event); err != nil {
+		log.S(ctx).Errorw("paypal webhook decode event", "transmissionID", transmission.ID, "err", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if err = h.dispatch(ctx, &event); err != nil {
+		log.S(ctx).Errorw("paypal webhook deal event", "eventID", event.ID, "eventType", event.EventType, "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) dispatch(ctx context.Context, event *Event) error {
+	h.mu.RLock()
+	fn, ok := h.handlers[event.EventType]
+	if !ok {
+		fn = h.fallback
+	}
+	h.mu.RUnlock()
+
+	if fn == nil {
+		return nil
+	}
+
+	return fn(ctx, event)
+}
+
+// OrderHandler adapts a handler of CHECKOUT.ORDER.* events.
+func OrderHandler(fn func(ctx context.Context, event *Event, order *paypal.Order) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var order paypal.Order
+		if err := event.DecodeResource(&order); err != nil {
+			return err
+		}
+		return fn(ctx, event, &order)
+	}
+}
+
+// AuthorizationHandler adapts a handler of PAYMENT.AUTHORIZATION.* events.
+func AuthorizationHandler(fn func(ctx context.Context, event *Event, authorization *paypal.Authorization) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var authorization paypal.Authorization
+		if err := event.DecodeResource(&authorization); err != nil {
+			return err
+		}
+		return fn(ctx, event, &authorization)
+	}
+}
+
+// CaptureHandler adapts a handler of PAYMENT.CAPTURE.* events except PAYMENT.CAPTURE.REFUNDED.
+func CaptureHandler(fn func(ctx context.Context, event *Event, capture *paypal.Capture) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var capture paypal.Capture
+		if err := event.DecodeResource(&capture); err != nil {
+			return err
+		}
+		return fn(ctx, event, &capture)
+	}
+}
+
+// RefundHandler adapts a handler of PAYMENT.CAPTURE.REFUNDED events, whose resource is the refund.
+func RefundHandler(fn func(ctx context.Context, event *Event, refund *paypal.Refund) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var refund paypal.Refund
+		if err := event.DecodeResource(&refund); err != nil {
+			return err
+		}
+		return fn(ctx, event, &refund)
+	}
+}
+
+// DisputeHandler adapts a handler of CUSTOMER.DISPUTE.* events.
+func DisputeHandler(fn func(ctx context.Context, event *Event, dispute *paypal.ShowDisputeDetailsResp) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var dispute paypal.ShowDisputeDetailsResp
+		if err := event.DecodeResource(&dispute); err != nil {
+			return err
+		}
+		return fn(ctx, event, &dispute)
+	}
+}
+
+// ProductHandler adapts a handler of CATALOG.PRODUCT.* events.
+func ProductHandler(fn func(ctx context.Context, event *Event, product *paypal.Product) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var product paypal.Product
+		if err := event.DecodeResource(&product); err != nil {
+			return err
+		}
+		return fn(ctx, event, &product)
+	}
+}
+
+// PlanHandler adapts a handler of BILLING.PLAN.* events.
+func PlanHandler(fn func(ctx context.Context, event *Event, plan *paypal.Plan) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var plan paypal.Plan
+		if err := event.DecodeResource(&plan); err != nil {
+			return err
+		}
+		return fn(ctx, event, &plan)
+	}
+}
+
+// SubscriptionHandler adapts a handler of BILLING.SUBSCRIPTION.* events.
+func SubscriptionHandler(fn func(ctx context.Context, event *Event, subscription *paypal.Subscription) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var subscription paypal.Subscription
+		if err := event.DecodeResource(&subscription); err != nil {
+			return err
+		}
+		return fn(ctx, event, &subscription)
+	}
+}
+
+// SaleHandler adapts a handler of PAYMENT.SALE.* events, the sale of a subscription carries its id in BillingAgreementID.
+func SaleHandler(fn func(ctx context.Context, event *Event, sale *paypal.Sale) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var sale paypal.Sale
+		if err := event.DecodeResource(&sale); err != nil {
+			return err
+		}
+		return fn(ctx, event, &sale)
+	}
+}
+
+// PayoutBatchHandler adapts a handler of PAYMENT.PAYOUTSBATCH.* events, the resource carries no items.
+func PayoutBatchHandler(fn func(ctx context.Context, event *Event, batch *paypal.PayoutBatch) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var batch paypal.PayoutBatch
+		if err := event.DecodeResource(&batch); err != nil {
+			return err
+		}
+		return fn(ctx, event, &batch)
+	}
+}
+
+// PayoutItemHandler adapts a handler of PAYMENT.PAYOUTS-ITEM.* events.
+func PayoutItemHandler(fn func(ctx context.Context, event *Event, item *paypal.PayoutItemDetail) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var item paypal.PayoutItemDetail
+		if err := event.DecodeResource(&item); err != nil {
+			return err
+		}
+		return fn(ctx, event, &item)
+	}
+}
+
+// PaymentTokenHandler adapts a handler of VAULT.PAYMENT-TOKEN.* events.
+func PaymentTokenHandler(fn func(ctx context.Context, event *Event, token *paypal.PaymentToken) error) HandlerFunc {
+	return func(ctx context.Context, event *Event) error {
+		var token paypal.PaymentToken
+		if err := event.DecodeResource(&token); err != nil {
+			return err
+		}
+		return fn(ctx, event, &token)
+	}
+}
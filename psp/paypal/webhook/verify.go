@@ -0,0 +1,261 @@
+package webhook
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linhoi/gopay/psp/paypal"
+)
+
+const (
+	// AuthAlgoSHA256WithRSA is the only algorithm paypal signs webhooks with.
+	AuthAlgoSHA256WithRSA = "SHA256withRSA"
+	// CertDNSName is the subject of the paypal webhook signing cert.
+	CertDNSName = "messageverificationcerts.paypal.com"
+)
+
+var (
+	// ErrInvalidSignature returns when the webhook is not signed by paypal or is signed for another webhook id.
+	ErrInvalidSignature = errors.New("paypal webhook signature invalid")
+	// ErrInvalidCert returns when the cert of the webhook is not a trusted paypal cert.
+	ErrInvalidCert = errors.New("paypal webhook cert invalid")
+	// ErrTransmissionExpired returns when the transmission is older than MaxAge, the webhook may be replayed.
+	ErrTransmissionExpired = errors.New("paypal webhook transmission expired")
+)
+
+// DefaultCertHosts are the hosts the cert is downloaded from, a leading dot matches the subdomains.
+var DefaultCertHosts = []string{".paypal.com"}
+
+// Verifier verifies the transmission of a webhook against its raw body.
+type Verifier interface {
+	Verify(ctx context.Context, transmission Transmission, body []byte) error
+}
+
+// OnlineVerifier verifies webhooks by /v1/notifications/verify-webhook-signature,
+// it costs a request to paypal per webhook.
+type OnlineVerifier struct {
+	Client    *paypal.Client
+	WebhookID string
+}
+
+func NewOnlineVerifier(client *paypal.Client, webhookID string) *OnlineVerifier {
+	return &OnlineVerifier{Client: client, WebhookID: webhookID}
+}
+
+func (v *OnlineVerifier) Verify(ctx context.Context, transmission Transmission, body []byte) error {
+	resp, err := v.Client.VerifyWebhookSignature(ctx, paypal.VerifyWebhookSignatureReq{
+		AuthAlgo:         transmission.AuthAlgo,
+		CertURL:          transmission.CertURL,
+		TransmissionID:   transmission.ID,
+		TransmissionSig:  transmission.Sig,
+		TransmissionTime: transmission.Time,
+		WebhookID:        v.WebhookID,
+		WebhookEvent:     body,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.VerificationStatus != paypal.VerificationStatusSuccess {
+		return fmt.Errorf("%w: verification status %s", ErrInvalidSignature, resp.VerificationStatus)
+	}
+
+	return nil
+}
+
+// OfflineVerifier verifies webhooks with the paypal cert locally, certs are cached until they expire.
+// https://developer.paypal.com/api/rest/webhooks/rest/#link-selfverificationmethod
+type OfflineVerifier struct {
+	WebhookID string
+	// HTTPClient downloads the certs, http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+	// Roots verifies the cert chain, the system roots are used if nil.
+	Roots *x509.CertPool
+	// CertHosts are the allowed hosts of PAYPAL-CERT-URL, DefaultCertHosts is used if empty.
+	CertHosts []string
+	// CertDNSName is the expected subject of the cert, CertDNSName is used if empty.
+	CertDNSName string
+	// MaxAge rejects transmissions older than it, zero disables the check.
+	MaxAge time.Duration
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+func NewOfflineVerifier(webhookID string) *OfflineVerifier {
+	return &OfflineVerifier{WebhookID: webhookID}
+}
+
+func (v *OfflineVerifier) Verify(ctx context.Context, transmission Transmission, body []byte) error {
+	if transmission.AuthAlgo != AuthAlgoSHA256WithRSA {
+		return fmt.Errorf("%w: unsupported auth algo %s", ErrInvalidSignature, transmission.AuthAlgo)
+	}
+
+	if v.MaxAge > 0 {
+		sentAt, err := time.Parse(time.RFC3339, transmission.Time)
+		if err != nil {
+			return fmt.Errorf("%w: bad transmission time %s", ErrInvalidSignature, transmission.Time)
+		}
+		if time.Since(sentAt) > v.MaxAge {
+			return ErrTransmissionExpired
+		}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(transmission.Sig)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	cert, err := v.cert(ctx, transmission.CertURL)
+	if err != nil {
+		return err
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: not a rsa key", ErrInvalidCert)
+	}
+
+	digest := sha256.Sum256([]byte(SignedMessage(transmission, v.WebhookID, body)))
+	if err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], sig); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// SignedMessage is the message paypal signs: <transmissionId>|<timeStamp>|<webhookId>|<crc32>.
+func SignedMessage(transmission Transmission, webhookID string, body []byte) string {
+	return strings.Join([]string{
+		transmission.ID,
+		transmission.Time,
+		webhookID,
+		strconv.FormatUint(uint64(crc32.ChecksumIEEE(body)), 10),
+	}, "|")
+}
+
+// cert returns the cached cert of certURL, or downloads and validates it.
+func (v *OfflineVerifier) cert(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	v.mu.Lock()
+	cert, ok := v.certs[certURL]
+	v.mu.Unlock()
+	if ok && time.Now().Before(cert.NotAfter) {
+		return cert, nil
+	}
+
+	if err := v.checkCertURL(certURL); err != nil {
+		return nil, err
+	}
+
+	cert, err := v.fetchCert(ctx, certURL)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	if v.certs == nil {
+		v.certs = make(map[string]*x509.Certificate)
+	}
+	v.certs[certURL] = cert
+	v.mu.Unlock()
+
+	return cert, nil
+}
+
+// checkCertURL refuses certs outside paypal, otherwise anyone could sign webhooks with their own cert.
+func (v *OfflineVerifier) checkCertURL(certURL string) error {
+	u, err := url.Parse(certURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCert, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: cert url %s is not https", ErrInvalidCert, certURL)
+	}
+
+	hosts := v.CertHosts
+	if len(hosts) == 0 {
+		hosts = DefaultCertHosts
+	}
+	host := u.Hostname()
+	for _, h := range hosts {
+		if host == h || (strings.HasPrefix(h, ".") && strings.HasSuffix(host, h)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: cert host %s is not allowed", ErrInvalidCert, host)
+}
+
+// fetchCert downloads the pem cert chain and verifies the leaf against the roots.
+func (v *OfflineVerifier) fetchCert(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download paypal cert %s: status %d", certURL, resp.StatusCode)
+	}
+
+	var chain []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCert, err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("%w: no certificate in %s", ErrInvalidCert, certURL)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	dnsName := v.CertDNSName
+	if dnsName == "" {
+		dnsName = CertDNSName
+	}
+	_, err = chain[0].Verify(x509.VerifyOptions{
+		DNSName:       dnsName,
+		Intermediates: intermediates,
+		Roots:         v.Roots,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCert, err)
+	}
+
+	return chain[0], nil
+}
@@ -1 +1,111 @@
+// Package webhook receives paypal webhook events.
+// https://developer.paypal.com/api/rest/webhooks/
 package webhook
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/linhoi/gopay/psp/paypal"
+)
+
+// transmission headers of a webhook
+const (
+	HeaderTransmissionID   = "PAYPAL-TRANSMISSION-ID"
+	HeaderTransmissionTime = "PAYPAL-TRANSMISSION-TIME"
+	HeaderTransmissionSig  = "PAYPAL-TRANSMISSION-SIG"
+	HeaderCertURL          = "PAYPAL-CERT-URL"
+	HeaderAuthAlgo         = "PAYPAL-AUTH-ALGO"
+)
+
+// event types https://developer.paypal.com/api/rest/webhooks/event-names/
+const (
+	EventCheckoutOrderApproved  = "CHECKOUT.ORDER.APPROVED"
+	EventCheckoutOrderCompleted = "CHECKOUT.ORDER.COMPLETED"
+
+	EventPaymentAuthorizationCreated = "PAYMENT.AUTHORIZATION.CREATED"
+	EventPaymentAuthorizationVoided  = "PAYMENT.AUTHORIZATION.VOIDED"
+
+	EventPaymentCaptureCompleted = "PAYMENT.CAPTURE.COMPLETED"
+	EventPaymentCaptureDenied    = "PAYMENT.CAPTURE.DENIED"
+	EventPaymentCapturePending   = "PAYMENT.CAPTURE.PENDING"
+	EventPaymentCaptureRefunded  = "PAYMENT.CAPTURE.REFUNDED"
+	EventPaymentCaptureReversed  = "PAYMENT.CAPTURE.REVERSED"
+
+	EventCustomerDisputeCreated  = "CUSTOMER.DISPUTE.CREATED"
+	EventCustomerDisputeUpdated  = "CUSTOMER.DISPUTE.UPDATED"
+	EventCustomerDisputeResolved = "CUSTOMER.DISPUTE.RESOLVED"
+
+	EventCatalogProductCreated = "CATALOG.PRODUCT.CREATED"
+	EventCatalogProductUpdated = "CATALOG.PRODUCT.UPDATED"
+
+	EventBillingPlanCreated                = "BILLING.PLAN.CREATED"
+	EventBillingPlanUpdated                = "BILLING.PLAN.UPDATED"
+	EventBillingPlanActivated              = "BILLING.PLAN.ACTIVATED"
+	EventBillingPlanDeactivated            = "BILLING.PLAN.DEACTIVATED"
+	EventBillingPlanPricingChangeActivated = "BILLING.PLAN.PRICING-CHANGE.ACTIVATED"
+
+	EventBillingSubscriptionCreated       = "BILLING.SUBSCRIPTION.CREATED"
+	EventBillingSubscriptionActivated     = "BILLING.SUBSCRIPTION.ACTIVATED"
+	EventBillingSubscriptionUpdated       = "BILLING.SUBSCRIPTION.UPDATED"
+	EventBillingSubscriptionReActivated   = "BILLING.SUBSCRIPTION.RE-ACTIVATED"
+	EventBillingSubscriptionSuspended     = "BILLING.SUBSCRIPTION.SUSPENDED"
+	EventBillingSubscriptionCancelled     = "BILLING.SUBSCRIPTION.CANCELLED"
+	EventBillingSubscriptionExpired       = "BILLING.SUBSCRIPTION.EXPIRED"
+	EventBillingSubscriptionPaymentFailed = "BILLING.SUBSCRIPTION.PAYMENT.FAILED"
+
+	// PAYMENT.SALE.* are the payments of subscriptions.
+	EventPaymentSaleCompleted = "PAYMENT.SALE.COMPLETED"
+	EventPaymentSaleDenied    = "PAYMENT.SALE.DENIED"
+	EventPaymentSalePending   = "PAYMENT.SALE.PENDING"
+	EventPaymentSaleRefunded  = "PAYMENT.SALE.REFUNDED"
+	EventPaymentSaleReversed  = "PAYMENT.SALE.REVERSED"
+
+	EventPaymentPayoutsBatchDenied     = "PAYMENT.PAYOUTSBATCH.DENIED"
+	EventPaymentPayoutsBatchProcessing = "PAYMENT.PAYOUTSBATCH.PROCESSING"
+	EventPaymentPayoutsBatchSuccess    = "PAYMENT.PAYOUTSBATCH.SUCCESS"
+
+	EventPaymentPayoutsItemBlocked   = "PAYMENT.PAYOUTS-ITEM.BLOCKED"
+	EventPaymentPayoutsItemCanceled  = "PAYMENT.PAYOUTS-ITEM.CANCELED"
+	EventPaymentPayoutsItemDenied    = "PAYMENT.PAYOUTS-ITEM.DENIED"
+	EventPaymentPayoutsItemFailed    = "PAYMENT.PAYOUTS-ITEM.FAILED"
+	EventPaymentPayoutsItemHeld      = "PAYMENT.PAYOUTS-ITEM.HELD"
+	EventPaymentPayoutsItemRefunded  = "PAYMENT.PAYOUTS-ITEM.REFUNDED"
+	EventPaymentPayoutsItemReturned  = "PAYMENT.PAYOUTS-ITEM.RETURNED"
+	EventPaymentPayoutsItemSucceeded = "PAYMENT.PAYOUTS-ITEM.SUCCEEDED"
+	EventPaymentPayoutsItemUnclaimed = "PAYMENT.PAYOUTS-ITEM.UNCLAIMED"
+
+	EventVaultPaymentTokenCreated = "VAULT.PAYMENT-TOKEN.CREATED"
+	EventVaultPaymentTokenDeleted = "VAULT.PAYMENT-TOKEN.DELETED"
+)
+
+// ErrMissingTransmission returns when a PAYPAL-TRANSMISSION-* header is missing, the request is not from paypal.
+var ErrMissingTransmission = errors.New("paypal webhook transmission header missing")
+
+// Transmission is the signature of a webhook carried by the PAYPAL-TRANSMISSION-* headers.
+type Transmission struct {
+	ID       string
+	Time     string
+	Sig      string
+	CertURL  string
+	AuthAlgo string
+}
+
+// ReadTransmission reads the transmission from the headers of a webhook.
+func ReadTransmission(header http.Header) (Transmission, error) {
+	t := Transmission{
+		ID:       header.Get(HeaderTransmissionID),
+		Time:     header.Get(HeaderTransmissionTime),
+		Sig:      header.Get(HeaderTransmissionSig),
+		CertURL:  header.Get(HeaderCertURL),
+		AuthAlgo: header.Get(HeaderAuthAlgo),
+	}
+	if t.ID == "" || t.Time == "" || t.Sig == "" || t.CertURL == "" || t.AuthAlgo == "" {
+		return t, ErrMissingTransmission
+	}
+
+	return t, nil
+}
+
+// Event is the envelope of a webhook event, Resource is decoded by the type of the event.
+type Event = paypal.WebhookEvent
@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linhoi/gopay/psp/paypal"
+)
+
+const (
+	testWebhookID = "1JE4291016473214C"
+	testEvent     = `{"id":"WH-2WR32451HC0233532-67976317FL4543714","event_type":"PAYMENT.CAPTURE.COMPLETED","resource_type":"capture","resource":{"id":"42311647XV020574X","status":"COMPLETED","amount":{"currency_code":"USD","value":"10.99"}}}`
+)
+
+// testCerts is a paypal like cert chain: root -> leaf of messageverificationcerts.paypal.com.
+type testCerts struct {
+	roots   *x509.CertPool
+	leafKey *rsa.PrivateKey
+	pem     []byte
+}
+
+func newTestCerts(t *testing.T) *testCerts {
+	rootKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, _ := x509.ParseCertificate(rootDER)
+
+	leafKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: CertDNSName},
+		DNSNames:     []string{CertDNSName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, &leafKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	var buf bytes.Buffer
+	buf.WriteString("-----BEGIN CERTIFICATE-----\n" + base64.StdEncoding.EncodeToString(leafDER) + "\n-----END CERTIFICATE-----\n")
+	buf.WriteString("-----BEGIN CERTIFICATE-----\n" + base64.StdEncoding.EncodeToString(rootDER) + "\n-----END CERTIFICATE-----\n")
+
+	return &testCerts{roots: roots, leafKey: leafKey, pem: buf.Bytes()}
+}
+
+func (c *testCerts) sign(t *testing.T, transmission Transmission, body []byte) string {
+	digest := sha256.Sum256([]byte(SignedMessage(transmission, testWebhookID, body)))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, c.leafKey, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func newWebhookRequest(transmission Transmission, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/paypal/webhook", bytes.NewBufferString(body))
+	r.Header.Set(HeaderTransmissionID, transmission.ID)
+	r.Header.Set(HeaderTransmissionTime, transmission.Time)
+	r.Header.Set(HeaderTransmissionSig, transmission.Sig)
+	r.Header.Set(HeaderCertURL, transmission.CertURL)
+	r.Header.Set(HeaderAuthAlgo, transmission.AuthAlgo)
+	return r
+}
+
+func TestHandler_OfflineVerifier(t *testing.T) {
+	certs := newTestCerts(t)
+	var certRequests int32
+	certServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&certRequests, 1)
+		_, _ = w.Write(certs.pem)
+	}))
+	defer certServer.Close()
+
+	verifier := NewOfflineVerifier(testWebhookID)
+	verifier.HTTPClient = certServer.Client()
+	verifier.Roots = certs.roots
+	verifier.CertHosts = []string{"127.0.0.1"}
+	verifier.MaxAge = time.Hour
+
+	var captured *paypal.Capture
+	handler := NewHandler(verifier)
+	handler.Handle(EventPaymentCaptureCompleted, CaptureHandler(func(ctx context.Context, event *Event, capture *paypal.Capture) error {
+		captured = capture
+		return nil
+	}))
+	handler.Handle(EventPaymentCaptureDenied, func(ctx context.Context, event *Event) error {
+		return errors.New("db is down")
+	})
+
+	valid := Transmission{
+		ID:       "69cd13f0-d67a-11e5-baa3-778b53f4ae55",
+		Time:     time.Now().UTC().Format(time.RFC3339),
+		CertURL:  certServer.URL + "/v1/notifications/certs/CERT-360caa42-fca2a594-a5cafa77",
+		AuthAlgo: AuthAlgoSHA256WithRSA,
+	}
+	valid.Sig = certs.sign(t, valid, []byte(testEvent))
+
+	deniedEvent := `{"id":"WH-1","event_type":"PAYMENT.CAPTURE.DENIED","resource":{}}`
+	denied := valid
+	denied.Sig = certs.sign(t, denied, []byte(deniedEvent))
+
+	foreignCert := valid
+	foreignCert.CertURL = "https://evil.example.com/cert.pem"
+
+	expired := valid
+	expired.Time = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	expired.Sig = certs.sign(t, expired, []byte(testEvent))
+
+	tests := []struct {
+		name         string
+		transmission Transmission
+		body         string
+		wantStatus   int
+	}{
+		{name: "valid", transmission: valid, body: testEvent, wantStatus: http.StatusOK},
+		{name: "tampered body", transmission: valid, body: testEvent + " ", wantStatus: http.StatusUnauthorized},
+		{name: "foreign cert", transmission: foreignCert, body: testEvent, wantStatus: http.StatusUnauthorized},
+		{name: "expired", transmission: expired, body: testEvent, wantStatus: http.StatusUnauthorized},
+		{name: "missing headers", transmission: Transmission{}, body: testEvent, wantStatus: http.StatusBadRequest},
+		{name: "handler failed", transmission: denied, body: deniedEvent, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, newWebhookRequest(tt.transmission, tt.body))
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	if captured == nil || captured.ID != "42311647XV020574X" || captured.Status != paypal.CaptureStatusCompleted {
+		t.Errorf("CaptureHandler() got = %+v", captured)
+	}
+	if n := atomic.LoadInt32(&certRequests); n != 1 {
+		t.Errorf("cert downloaded %d times, want 1", n)
+	}
+}
+
+func TestOfflineVerifier_UntrustedCert(t *testing.T) {
+	certs := newTestCerts(t)
+	certServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(certs.pem)
+	}))
+	defer certServer.Close()
+
+	verifier := NewOfflineVerifier(testWebhookID)
+	verifier.HTTPClient = certServer.Client()
+	verifier.Roots = x509.NewCertPool()
+	verifier.CertHosts = []string{"127.0.0.1"}
+
+	transmission := Transmission{ID: "1", Time: time.Now().UTC().Format(time.RFC3339), CertURL: certServer.URL + "/cert", AuthAlgo: AuthAlgoSHA256WithRSA}
+	transmission.Sig = certs.sign(t, transmission, []byte(testEvent))
+
+	err := verifier.Verify(context.Background(), transmission, []byte(testEvent))
+	if !errors.Is(err, ErrInvalidCert) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrInvalidCert)
+	}
+}
+
+func TestOnlineVerifier(t *testing.T) {
+	var got paypal.VerifyWebhookSignatureReq
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/oauth2/token" {
+			_, _ = w.Write([]byte(`{"access_token":"A21AA-test","expires_in":32400}`))
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		status := paypal.VerificationStatusFailure
+		if got.TransmissionSig == "good" {
+			status = paypal.VerificationStatusSuccess
+		}
+		_ = json.NewEncoder(w).Encode(paypal.VerifyWebhookSignatureResp{VerificationStatus: status})
+	}))
+	defer server.Close()
+
+	verifier := NewOnlineVerifier(paypal.NewClient(paypal.Config{Host: server.URL}), testWebhookID)
+	transmission := Transmission{ID: "1", Time: "2022-01-01T00:00:00Z", Sig: "good", CertURL: "https://api.paypal.com/cert", AuthAlgo: AuthAlgoSHA256WithRSA}
+
+	if err := verifier.Verify(context.Background(), transmission, []byte(testEvent)); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if got.WebhookID != testWebhookID || string(got.WebhookEvent) != testEvent {
+		t.Errorf("Verify() request = %+v", got)
+	}
+
+	transmission.Sig = "bad"
+	if err := verifier.Verify(context.Background(), transmission, []byte(testEvent)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
@@ -0,0 +1,136 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_EnsureWebhook(t *testing.T) {
+	webhooks := map[string]interface{}{
+		"webhooks": []map[string]interface{}{
+			{"id": "40Y916089Y8324740", "url": "https://example.com/paypal/webhook", "event_types": []map[string]string{
+				{"name": "PAYMENT.CAPTURE.COMPLETED"}, {"name": "PAYMENT.CAPTURE.REFUNDED"},
+			}},
+			{"id": "0EH40505U7160970P", "url": "https://staging.example.com/paypal/webhook", "event_types": []map[string]string{
+				{"name": "PAYMENT.CAPTURE.COMPLETED"},
+			}},
+		},
+	}
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"GET " + webhooksPath: {body: webhooks},
+		"POST " + webhooksPath: {status: http.StatusCreated, body: map[string]interface{}{
+			"id": "8PT597110X687430LKGECATA", "url": "https://dev.example.com/paypal/webhook", "event_types": []map[string]string{{"name": "*"}},
+		}},
+		"PATCH " + webhooksPath + "/0EH40505U7160970P": {body: map[string]interface{}{
+			"id": "0EH40505U7160970P", "url": "https://staging.example.com/paypal/webhook", "event_types": []map[string]string{
+				{"name": "PAYMENT.CAPTURE.COMPLETED"}, {"name": "PAYMENT.CAPTURE.REFUNDED"},
+			},
+		}},
+	})
+	ctx := context.Background()
+	eventTypes := []string{"PAYMENT.CAPTURE.REFUNDED", "PAYMENT.CAPTURE.COMPLETED"}
+
+	tests := []struct {
+		name         string
+		url          string
+		eventTypes   []string
+		wantID       string
+		wantRequests []string
+	}{
+		{
+			name:         "up to date",
+			url:          "https://example.com/paypal/webhook",
+			eventTypes:   eventTypes,
+			wantID:       "40Y916089Y8324740",
+			wantRequests: []string{"GET " + webhooksPath},
+		},
+		{
+			name:         "update event types",
+			url:          "https://staging.example.com/paypal/webhook",
+			eventTypes:   eventTypes,
+			wantID:       "0EH40505U7160970P",
+			wantRequests: []string{"GET " + webhooksPath, "PATCH " + webhooksPath + "/0EH40505U7160970P"},
+		},
+		{
+			name:         "create",
+			url:          "https://dev.example.com/paypal/webhook",
+			eventTypes:   []string{EventTypeAll},
+			wantID:       "8PT597110X687430LKGECATA",
+			wantRequests: []string{"GET " + webhooksPath, "POST " + webhooksPath},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.requests = nil
+			webhook, err := c.EnsureWebhook(ctx, tt.url, tt.eventTypes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if webhook.ID != tt.wantID {
+				t.Errorf("EnsureWebhook() got = %+v, want id %v", webhook, tt.wantID)
+			}
+
+			var got []string
+			for _, req := range mock.requests {
+				got = append(got, req.Method+" "+req.URL.Path)
+			}
+			if !reflect.DeepEqual(got, tt.wantRequests) {
+				t.Errorf("EnsureWebhook() requests = %v, want %v", got, tt.wantRequests)
+			}
+		})
+	}
+
+	want := `[{"op":"replace","path":"/event_types","value":[{"name":"PAYMENT.CAPTURE.REFUNDED"},{"name":"PAYMENT.CAPTURE.COMPLETED"}]}]`
+	if got := string(mock.bodies[len(mock.bodies)-3]); got != want {
+		t.Errorf("UpdateWebhook() body = %s, want %s", got, want)
+	}
+}
+
+func TestClient_WebhookManagement(t *testing.T) {
+	c, mock := newMockClient(t, map[string]mockResponse{
+		"POST " + webhooksPath: {status: http.StatusBadRequest, body: map[string]interface{}{
+			"name": "WEBHOOK_URL_ALREADY_EXISTS", "message": "Webhook URL already exists.",
+		}},
+		"DELETE " + webhooksPath + "/40Y916089Y8324740": {status: http.StatusNoContent},
+		"GET " + webhookEventTypesPath: {body: map[string]interface{}{
+			"event_types": []map[string]interface{}{
+				{"name": "PAYMENT.CAPTURE.COMPLETED", "description": "A payment capture completes.", "status": "ENABLED", "resource_versions": []string{"2.0"}},
+			},
+		}},
+		"POST " + simulateEventPath: {status: http.StatusCreated, body: map[string]interface{}{
+			"id": "WH-9Y180613C5171350R-3A568107UP261041K", "event_type": "PAYMENT.CAPTURE.COMPLETED", "resource": map[string]string{"id": "42311647XV020574X"},
+		}},
+	})
+	ctx := context.Background()
+
+	if _, err := c.CreateWebhook(ctx, "https://example.com/paypal/webhook", []string{EventTypeAll}); !errors.Is(err, ErrWebhookURLExists) {
+		t.Errorf("CreateWebhook() err = %v, want %v", err, ErrWebhookURLExists)
+	}
+
+	if err := c.DeleteWebhook(ctx, "40Y916089Y8324740"); err != nil {
+		t.Errorf("DeleteWebhook() err = %v", err)
+	}
+	if err := c.DeleteWebhook(ctx, "UNKNOWN"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("DeleteWebhook() err = %v, want %v", err, ErrResourceNotFound)
+	}
+
+	eventTypes, err := c.ListEventTypes(ctx)
+	if err != nil || len(eventTypes) != 1 || !reflect.DeepEqual(eventTypes[0].ResourceVersions, []string{"2.0"}) {
+		t.Errorf("ListEventTypes() got = %+v, err = %v", eventTypes, err)
+	}
+
+	event, err := c.SimulateEvent(ctx, SimulateEventReq{WebhookID: "40Y916089Y8324740", EventType: "PAYMENT.CAPTURE.COMPLETED"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var capture Capture
+	if err = event.DecodeResource(&capture); err != nil || capture.ID != "42311647XV020574X" {
+		t.Errorf("SimulateEvent() resource = %+v, err = %v", capture, err)
+	}
+	if got := string(mock.bodies[len(mock.bodies)-1]); got != `{"webhook_id":"40Y916089Y8324740","event_type":"PAYMENT.CAPTURE.COMPLETED"}` {
+		t.Errorf("SimulateEvent() body = %s", got)
+	}
+}